@@ -0,0 +1,62 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/spiffe/spike/app/keeper/internal/state"
+	"github.com/spiffe/spike/internal/entity/v1/reqres"
+	"github.com/spiffe/spike/internal/net"
+)
+
+// routeShardShow returns this Keeper's Shamir share of the root key,
+// never the full key. It is the read side of
+// Nexus's recovery path: Nexus collects shares from at least `t` Keepers
+// and reconstructs the root key itself, so no single Keeper response ever
+// carries enough information to leak the whole secret.
+func routeShardShow(r *http.Request, w http.ResponseWriter) {
+	log.Println("routeShardShow:", r.Method, r.URL.Path, r.URL.RawQuery)
+
+	body := net.ReadRequestBody(r, w)
+	if body == nil {
+		return
+	}
+
+	var req reqres.ShardShowRequest
+	if err := net.HandleRequestError(w, json.Unmarshal(body, &req)); err != nil {
+		log.Println("routeShardShow: Problem handling request:", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		_, err := io.WriteString(w, "")
+		if err != nil {
+			log.Println("routeShardShow: Problem writing response:", err.Error())
+		}
+		return
+	}
+
+	shard := state.CurrentShard()
+
+	res := reqres.ShardShowResponse{
+		Version: shard.Version,
+		Index:   shard.Index,
+		Share:   hex.EncodeToString(shard.Share),
+		MAC:     hex.EncodeToString(shard.MAC),
+	}
+	md, err := json.Marshal(res)
+	if err != nil {
+		log.Println("routeShardShow: Problem generating response:", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = io.WriteString(w, string(md))
+	if err != nil {
+		log.Println("routeShardShow: Problem writing response:", err.Error())
+	}
+}
@@ -0,0 +1,76 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/spiffe/spike/app/keeper/internal/state"
+	"github.com/spiffe/spike/internal/entity/v1/reqres"
+	"github.com/spiffe/spike/internal/net"
+)
+
+// routeShardUpdate receives a single Shamir share of the root key from
+// Nexus and replaces this Keeper's in-memory copy, via state.SetShard. It
+// is the write side of Nexus's distribution path: net.UpdateShard calls
+// this instead of the full-key route that routeShow used to be paired
+// with, so no single Keeper ever holds more than its own share.
+func routeShardUpdate(r *http.Request, w http.ResponseWriter) {
+	log.Println("routeShardUpdate:", r.Method, r.URL.Path, r.URL.RawQuery)
+
+	body := net.ReadRequestBody(r, w)
+	if body == nil {
+		return
+	}
+
+	var req reqres.ShardUpdateRequest
+	if err := net.HandleRequestError(w, json.Unmarshal(body, &req)); err != nil {
+		log.Println("routeShardUpdate: Problem handling request:", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		_, err := io.WriteString(w, "")
+		if err != nil {
+			log.Println("routeShardUpdate: Problem writing response:", err.Error())
+		}
+		return
+	}
+
+	share, err := hex.DecodeString(req.Share)
+	if err != nil {
+		log.Println("routeShardUpdate: Problem decoding share:", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	mac, err := hex.DecodeString(req.MAC)
+	if err != nil {
+		log.Println("routeShardUpdate: Problem decoding mac:", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	state.SetShard(state.Shard{
+		Version: req.Version,
+		Index:   req.Index,
+		Share:   share,
+		MAC:     mac,
+	})
+
+	res := reqres.ShardUpdateResponse{}
+	md, err := json.Marshal(res)
+	if err != nil {
+		log.Println("routeShardUpdate: Problem generating response:", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, err = io.WriteString(w, string(md))
+	if err != nil {
+		log.Println("routeShardUpdate: Problem writing response:", err.Error())
+	}
+}
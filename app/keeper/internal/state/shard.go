@@ -0,0 +1,38 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import "sync"
+
+// Shard is the Shamir share of the root key that this Keeper is
+// responsible for backing up, along with the HMAC tag Nexus computed over
+// it so that recovery can detect a corrupted share.
+type Shard struct {
+	Version uint32
+	Index   byte
+	Share   []byte
+	MAC     []byte
+}
+
+var (
+	shardMu sync.RWMutex
+	shard   Shard
+)
+
+// SetShard replaces the in-memory Shamir share this Keeper holds.
+// routeShardUpdate calls this each time Nexus pushes this Keeper a share.
+func SetShard(s Shard) {
+	shardMu.Lock()
+	defer shardMu.Unlock()
+	shard = s
+}
+
+// CurrentShard returns this Keeper's current Shamir share. The zero value
+// (Version 0, empty Share) means no share has been received yet.
+func CurrentShard() Shard {
+	shardMu.RLock()
+	defer shardMu.RUnlock()
+	return shard
+}
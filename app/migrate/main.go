@@ -0,0 +1,52 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+// Command spike-migrate copies every record from one state.Store driver
+// to another, so an operator can move Nexus or Keeper from one backend
+// (e.g. bbolt) to another (e.g. postgres) without downtime: point it at
+// the old and new DSNs, confirm the new driver looks right, then switch
+// the service over.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/spiffe/spike/internal/store"
+)
+
+func main() {
+	srcDriver := flag.String("src-driver", "", "source driver name (one of store.Available())")
+	srcDSN := flag.String("src-dsn", "", "source driver connection string")
+	dstDriver := flag.String("dst-driver", "", "destination driver name (one of store.Available())")
+	dstDSN := flag.String("dst-dsn", "", "destination driver connection string")
+	prefix := flag.String("prefix", "", "only migrate keys with this prefix (default: all keys)")
+	flag.Parse()
+
+	if *srcDriver == "" || *dstDriver == "" {
+		log.Fatal("spike-migrate: -src-driver and -dst-driver are required; available drivers: ",
+			store.Available())
+	}
+
+	src, err := store.New(*srcDriver, *srcDSN)
+	if err != nil {
+		log.Fatalf("spike-migrate: opening source: %v", err)
+	}
+	defer src.Close()
+
+	dst, err := store.New(*dstDriver, *dstDSN)
+	if err != nil {
+		log.Fatalf("spike-migrate: opening destination: %v", err)
+	}
+	defer dst.Close()
+
+	copied, err := store.Migrate(context.Background(), src, dst, *prefix)
+	if err != nil {
+		log.Fatalf("spike-migrate: migration failed after copying %d records: %v", copied, err)
+	}
+
+	fmt.Printf("spike-migrate: copied %d record(s) from %s to %s\n", copied, *srcDriver, *dstDriver)
+}
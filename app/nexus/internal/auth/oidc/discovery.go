@@ -0,0 +1,74 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Discovery holds the subset of an OIDC provider's
+// /.well-known/openid-configuration document that Nexus needs to drive
+// the authorization code + PKCE flow.
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+var discoveryClient = &http.Client{Timeout: 10 * time.Second}
+
+// Discover fetches and parses the OIDC discovery document for issuer.
+// Keycloak, Google, GitHub and any spec-compliant provider all expose
+// this at `<issuer>/.well-known/openid-configuration`.
+func Discover(issuer string) (Discovery, error) {
+	var d Discovery
+
+	endpoint := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	if _, err := url.Parse(endpoint); err != nil {
+		return d, fmt.Errorf("oidc: invalid issuer URL %q: %w", issuer, err)
+	}
+
+	res, err := discoveryClient.Get(endpoint)
+	if err != nil {
+		return d, fmt.Errorf("oidc: fetching discovery document: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return d, fmt.Errorf("oidc: discovery document returned status %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&d); err != nil {
+		return d, fmt.Errorf("oidc: decoding discovery document: %w", err)
+	}
+
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return d, fmt.Errorf("oidc: discovery document for %q is missing required endpoints", issuer)
+	}
+
+	return d, nil
+}
+
+// AuthorizationURL builds the URL Nexus redirects the admin's browser to
+// in order to start the authorization code + PKCE flow.
+func AuthorizationURL(d Discovery, clientID, redirectURL, state, codeChallenge, nonce string) string {
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {clientID},
+		"redirect_uri":          {redirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	return d.AuthorizationEndpoint + "?" + q.Encode()
+}
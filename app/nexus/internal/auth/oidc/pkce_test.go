@@ -0,0 +1,43 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+)
+
+func TestNewPKCE_ChallengeMatchesVerifier(t *testing.T) {
+	verifier, challenge, state, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE returned error: %v", err)
+	}
+	if verifier == "" || challenge == "" || state == "" {
+		t.Fatalf("NewPKCE returned an empty field: verifier=%q challenge=%q state=%q",
+			verifier, challenge, state)
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+	if challenge != want {
+		t.Fatalf("challenge = %q, want S256(verifier) = %q", challenge, want)
+	}
+}
+
+func TestNewPKCE_GeneratesDistinctValuesEachCall(t *testing.T) {
+	v1, c1, s1, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE returned error: %v", err)
+	}
+	v2, c2, s2, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("NewPKCE returned error: %v", err)
+	}
+
+	if v1 == v2 || c1 == c2 || s1 == s2 {
+		t.Fatalf("NewPKCE produced repeated output across calls")
+	}
+}
@@ -0,0 +1,97 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TokenResponse is the subset of a standard OIDC token endpoint response
+// that Nexus cares about.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+var tokenClient = &http.Client{Timeout: 10 * time.Second}
+
+// ExchangeCode trades an authorization code and its matching PKCE
+// code_verifier for tokens at the provider's token_endpoint.
+func ExchangeCode(
+	d Discovery, clientID, clientSecret, redirectURL, code, codeVerifier string,
+) (TokenResponse, error) {
+	var out TokenResponse
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {clientID},
+		"code_verifier": {codeVerifier},
+	}
+	if clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return out, fmt.Errorf("oidc: building token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := tokenClient.Do(req)
+	if err != nil {
+		return out, fmt.Errorf("oidc: calling token endpoint: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return out, fmt.Errorf("oidc: token endpoint returned status %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return out, fmt.Errorf("oidc: decoding token response: %w", err)
+	}
+
+	if out.IDToken == "" {
+		return out, fmt.Errorf("oidc: token response did not include an id_token")
+	}
+
+	return out, nil
+}
+
+// ClaimAuthorized reports whether claims[claim] matches one of
+// allowedValues. The claim value may be a single string (e.g. "email") or
+// a list of strings (e.g. "groups"); both shapes are checked.
+func ClaimAuthorized(claims map[string]any, claim string, allowedValues []string) bool {
+	if len(allowedValues) == 0 {
+		return false
+	}
+
+	allowed := make(map[string]bool, len(allowedValues))
+	for _, v := range allowedValues {
+		allowed[v] = true
+	}
+
+	switch v := claims[claim].(type) {
+	case string:
+		return allowed[v]
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && allowed[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import "testing"
+
+func TestClaimAuthorized_StringClaim(t *testing.T) {
+	claims := map[string]any{"email": "admin@example.com"}
+	if !ClaimAuthorized(claims, "email", []string{"admin@example.com"}) {
+		t.Fatalf("expected matching email claim to be authorized")
+	}
+	if ClaimAuthorized(claims, "email", []string{"someone-else@example.com"}) {
+		t.Fatalf("expected non-matching email claim to be unauthorized")
+	}
+}
+
+func TestClaimAuthorized_ListClaim(t *testing.T) {
+	claims := map[string]any{"groups": []any{"engineers", "admins"}}
+	if !ClaimAuthorized(claims, "groups", []string{"admins"}) {
+		t.Fatalf("expected a matching group in the list to be authorized")
+	}
+	if ClaimAuthorized(claims, "groups", []string{"finance"}) {
+		t.Fatalf("expected no matching group to be unauthorized")
+	}
+}
+
+func TestClaimAuthorized_NoAllowedValues(t *testing.T) {
+	claims := map[string]any{"email": "admin@example.com"}
+	if ClaimAuthorized(claims, "email", nil) {
+		t.Fatalf("expected an empty allow-list to never authorize")
+	}
+}
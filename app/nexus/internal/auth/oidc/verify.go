@@ -0,0 +1,190 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+var jwksClient = &http.Client{Timeout: 10 * time.Second}
+
+func fetchJWKS(jwksURI string) (jwks, error) {
+	var out jwks
+
+	res, err := jwksClient.Get(jwksURI)
+	if err != nil {
+		return out, fmt.Errorf("oidc: fetching JWKS: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return out, fmt.Errorf("oidc: JWKS endpoint returned status %d", res.StatusCode)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return out, fmt.Errorf("oidc: decoding JWKS: %w", err)
+	}
+
+	return out, nil
+}
+
+func (k jwk) publicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, fmt.Errorf("oidc: unsupported JWK key type %q", k.Kty)
+	}
+
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: decoding JWK modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: decoding JWK exponent: %w", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+// VerifyIDToken validates an RS256-signed ID token's signature against
+// the provider's JWKS, and checks the standard `iss`, `aud`, `exp` and
+// `nonce` claims. On success it returns the decoded claim set so the
+// caller can map a configured claim (e.g. "email" or "groups") to admin
+// authorization.
+func VerifyIDToken(
+	d Discovery, rawIDToken, clientID, expectedNonce string,
+) (map[string]any, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("oidc: malformed ID token")
+	}
+
+	header, err := decodeSegment(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("oidc: decoding ID token header: %w", err)
+	}
+	var hdr struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(header, &hdr); err != nil {
+		return nil, fmt.Errorf("oidc: parsing ID token header: %w", err)
+	}
+	if hdr.Alg != "RS256" {
+		return nil, fmt.Errorf("oidc: unsupported ID token signing algorithm %q", hdr.Alg)
+	}
+
+	set, err := fetchJWKS(d.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	var key *jwk
+	for i := range set.Keys {
+		if set.Keys[i].Kid == hdr.Kid {
+			key = &set.Keys[i]
+			break
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("oidc: no JWKS key matches ID token kid %q", hdr.Kid)
+	}
+
+	pub, err := key.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("oidc: decoding ID token signature: %w", err)
+	}
+	signedInput := parts[0] + "." + parts[1]
+	digest := sha256.Sum256([]byte(signedInput))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("oidc: ID token signature verification failed: %w", err)
+	}
+
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("oidc: decoding ID token claims: %w", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("oidc: parsing ID token claims: %w", err)
+	}
+
+	if err := validateClaims(claims, d.Issuer, clientID, expectedNonce); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func validateClaims(claims map[string]any, issuer, clientID, expectedNonce string) error {
+	if iss, _ := claims["iss"].(string); iss != issuer {
+		return fmt.Errorf("oidc: unexpected issuer %q", iss)
+	}
+
+	if !audienceContains(claims["aud"], clientID) {
+		return fmt.Errorf("oidc: token audience does not include client %q", clientID)
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("oidc: token is missing an exp claim")
+	}
+	if time.Now().After(time.Unix(int64(exp), 0)) {
+		return fmt.Errorf("oidc: token has expired")
+	}
+
+	nonce, _ := claims["nonce"].(string)
+	if nonce == "" || nonce != expectedNonce {
+		return fmt.Errorf("oidc: token nonce does not match the authorization request")
+	}
+
+	return nil
+}
+
+func audienceContains(aud any, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []any:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,223 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testClientID = "test-client"
+	testKid      = "test-key"
+)
+
+// testJWKSServer serves a JWKS document exposing pub under testKid, the
+// fixture VerifyIDToken's fetchJWKS call resolves against.
+func testJWKSServer(t *testing.T, pub *rsa.PublicKey) *httptest.Server {
+	t.Helper()
+
+	set := jwks{Keys: []jwk{{
+		Kty: "RSA",
+		Kid: testKid,
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// signIDToken builds and RS256-signs a JWT from claims using key, with
+// the given header alg - letting tests mint a token with a non-RS256 alg
+// to exercise VerifyIDToken's algorithm check.
+func signIDToken(t *testing.T, key *rsa.PrivateKey, alg string, claims map[string]any) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": testKid})
+	if err != nil {
+		t.Fatalf("marshaling header: %v", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshaling claims: %v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims(nonce string) map[string]any {
+	return map[string]any{
+		"iss":   testIssuer,
+		"aud":   testClientID,
+		"exp":   float64(time.Now().Add(time.Hour).Unix()),
+		"nonce": nonce,
+	}
+}
+
+func TestVerifyIDToken_AcceptsValidToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	token := signIDToken(t, key, "RS256", validClaims("nonce-1"))
+
+	claims, err := VerifyIDToken(d, token, testClientID, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken returned error: %v", err)
+	}
+	if claims["iss"] != testIssuer {
+		t.Fatalf("claims[iss] = %v, want %v", claims["iss"], testIssuer)
+	}
+}
+
+func TestVerifyIDToken_AcceptsAudienceAsArray(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	claims := validClaims("nonce-1")
+	claims["aud"] = []any{"someone-else", testClientID}
+	token := signIDToken(t, key, "RS256", claims)
+
+	if _, err := VerifyIDToken(d, token, testClientID, "nonce-1"); err != nil {
+		t.Fatalf("VerifyIDToken rejected a client present in an array aud: %v", err)
+	}
+}
+
+func TestVerifyIDToken_RejectsWrongIssuer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	claims := validClaims("nonce-1")
+	claims["iss"] = "https://attacker.example.com"
+	token := signIDToken(t, key, "RS256", claims)
+
+	if _, err := VerifyIDToken(d, token, testClientID, "nonce-1"); err == nil {
+		t.Fatalf("VerifyIDToken accepted a token with the wrong issuer")
+	}
+}
+
+func TestVerifyIDToken_RejectsAudienceMismatch_StringForm(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	claims := validClaims("nonce-1")
+	claims["aud"] = "someone-else"
+	token := signIDToken(t, key, "RS256", claims)
+
+	if _, err := VerifyIDToken(d, token, testClientID, "nonce-1"); err == nil {
+		t.Fatalf("VerifyIDToken accepted a token for a different audience")
+	}
+}
+
+func TestVerifyIDToken_RejectsAudienceMismatch_ArrayForm(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	claims := validClaims("nonce-1")
+	claims["aud"] = []any{"someone-else", "another-client"}
+	token := signIDToken(t, key, "RS256", claims)
+
+	if _, err := VerifyIDToken(d, token, testClientID, "nonce-1"); err == nil {
+		t.Fatalf("VerifyIDToken accepted a token whose audience array excludes the client")
+	}
+}
+
+func TestVerifyIDToken_RejectsExpiredToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	claims := validClaims("nonce-1")
+	claims["exp"] = float64(time.Now().Add(-time.Hour).Unix())
+	token := signIDToken(t, key, "RS256", claims)
+
+	if _, err := VerifyIDToken(d, token, testClientID, "nonce-1"); err == nil {
+		t.Fatalf("VerifyIDToken accepted an expired token")
+	}
+}
+
+func TestVerifyIDToken_RejectsWrongNonce(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	token := signIDToken(t, key, "RS256", validClaims("nonce-1"))
+
+	if _, err := VerifyIDToken(d, token, testClientID, "nonce-2"); err == nil {
+		t.Fatalf("VerifyIDToken accepted a token with a mismatched nonce")
+	}
+}
+
+func TestVerifyIDToken_RejectsMissingNonce(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	claims := validClaims("")
+	delete(claims, "nonce")
+	token := signIDToken(t, key, "RS256", claims)
+
+	if _, err := VerifyIDToken(d, token, testClientID, "nonce-1"); err == nil {
+		t.Fatalf("VerifyIDToken accepted a token with no nonce claim")
+	}
+}
+
+func TestVerifyIDToken_RejectsNonRS256Algorithm(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	d := Discovery{Issuer: testIssuer, JWKSURI: testJWKSServer(t, &key.PublicKey).URL}
+
+	token := signIDToken(t, key, "HS256", validClaims("nonce-1"))
+
+	if _, err := VerifyIDToken(d, token, testClientID, "nonce-1"); err == nil {
+		t.Fatalf("VerifyIDToken accepted a token signed with a non-RS256 algorithm")
+	}
+}
@@ -0,0 +1,126 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+// Package password hashes and verifies the admin password. New
+// credentials are stored as self-describing Argon2id PHC strings; the
+// verifier also transparently accepts the legacy bare-hex
+// PBKDF2-SHA256 records that routeAdminLogin and updateStateForInit used
+// to produce, and reports when a successful legacy verification should be
+// upgraded in place.
+package password
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/spiffe/spike/app/nexus/internal/env"
+)
+
+// phcPrefix identifies the self-describing Argon2id encoding. Anything
+// that doesn't start with it is assumed to be a legacy bare-hex
+// PBKDF2-SHA256 hash, the only format Nexus ever produced before.
+const phcPrefix = "$argon2id$"
+
+// ErrInvalidEncoding is returned when a stored credential is neither a
+// well-formed Argon2id PHC string nor a legacy hex-encoded hash.
+var ErrInvalidEncoding = errors.New("password: invalid credential encoding")
+
+// Hash derives a new Argon2id hash for password using the parameters
+// configured via env.Argon2*, and returns it in the standard PHC format:
+//
+//	$argon2id$v=19$m=<memory>,t=<time>,p=<parallelism>$<b64 salt>$<b64 hash>
+func Hash(password string) (string, error) {
+	salt := make([]byte, env.Argon2SaltLength())
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("password: generating salt: %w", err)
+	}
+
+	time := env.Argon2Time()
+	memory := env.Argon2MemoryKiB()
+	parallelism := env.Argon2Parallelism()
+	keyLen := env.Argon2KeyLength()
+
+	hash := argon2.IDKey([]byte(password), salt, time, memory, parallelism, keyLen)
+
+	return fmt.Sprintf(
+		"%sv=19$m=%d,t=%d,p=%d$%s$%s",
+		phcPrefix, memory, time, parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// Verify checks password against a stored credential. If encoded is a
+// PHC-formatted Argon2id record, legacySalt is ignored. If encoded is a
+// legacy bare-hex PBKDF2-SHA256 hash, legacySalt must be the matching
+// hex-encoded salt that routeAdminLogin used to read from
+// state.AdminCredentials().Salt.
+//
+// needsRehash is true when verification succeeded against a legacy
+// record; the caller should then call Hash and persist the result so
+// that record is upgraded to Argon2id, the same pattern modern web login
+// systems use to migrate hashes on successful login.
+func Verify(password, encoded, legacySalt string) (ok bool, needsRehash bool, err error) {
+	if strings.HasPrefix(encoded, phcPrefix) {
+		ok, err := verifyArgon2id(password, encoded)
+		return ok, false, err
+	}
+	ok, err = verifyLegacyPbkdf2(password, encoded, legacySalt)
+	return ok, ok, err
+}
+
+func verifyArgon2id(password, encoded string) (bool, error) {
+	var memory, time uint32
+	var parallelism uint8
+	var b64Salt, b64Hash string
+
+	// encoded looks like "$argon2id$v=19$m=..,t=..,p=..$salt$hash".
+	fields := strings.Split(encoded, "$")
+	if len(fields) != 6 {
+		return false, ErrInvalidEncoding
+	}
+	if _, err := fmt.Sscanf(fields[3], "m=%d,t=%d,p=%d", &memory, &time, &parallelism); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	b64Salt, b64Hash = fields[4], fields[5]
+
+	salt, err := base64.RawStdEncoding.DecodeString(b64Salt)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	want, err := base64.RawStdEncoding.DecodeString(b64Hash)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+
+	got := argon2.IDKey([]byte(password), salt, time, memory, parallelism, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
+
+func verifyLegacyPbkdf2(password, hexHash, hexSalt string) (bool, error) {
+	salt, err := hex.DecodeString(hexSalt)
+	if err != nil {
+		return false, fmt.Errorf("password: decoding legacy salt: %w", err)
+	}
+	want, err := hex.DecodeString(hexHash)
+	if err != nil {
+		return false, fmt.Errorf("password: decoding legacy hash: %w", err)
+	}
+
+	got := pbkdf2.Key(
+		[]byte(password), salt,
+		env.Pbkdf2IterationCount(), env.ShaHashLength(), sha256.New,
+	)
+	return hmac.Equal(got, want), nil
+}
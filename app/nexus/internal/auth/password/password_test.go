@@ -0,0 +1,80 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package password
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func TestHashVerify_RoundTrip(t *testing.T) {
+	encoded, err := Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Hash returned error: %v", err)
+	}
+	if !strings.HasPrefix(encoded, phcPrefix) {
+		t.Fatalf("Hash did not produce a PHC-formatted record: %q", encoded)
+	}
+
+	ok, needsRehash, err := Verify("correct horse battery staple", encoded, "")
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("Verify rejected the correct password against its own hash")
+	}
+	if needsRehash {
+		t.Fatalf("Verify flagged a fresh Argon2id record as needing a rehash")
+	}
+}
+
+func TestVerify_RejectsWrongPassword(t *testing.T) {
+	encoded, err := Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Hash returned error: %v", err)
+	}
+
+	ok, _, err := Verify("wrong password", encoded, "")
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if ok {
+		t.Fatalf("Verify accepted the wrong password")
+	}
+}
+
+func TestVerify_AcceptsLegacyPbkdf2AndFlagsRehash(t *testing.T) {
+	password := "legacy-admin-password"
+	salt := []byte("0123456789abcdef")
+	hash := pbkdf2.Key([]byte(password), salt, 600_000, 32, sha256.New)
+
+	ok, needsRehash, err := Verify(password, hex.EncodeToString(hash), hex.EncodeToString(salt))
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("Verify rejected a valid legacy PBKDF2 hash")
+	}
+	if !needsRehash {
+		t.Fatalf("Verify did not flag a legacy record for rehashing")
+	}
+}
+
+func TestVerify_RejectsWrongLegacyPassword(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	hash := pbkdf2.Key([]byte("the-real-password"), salt, 600_000, 32, sha256.New)
+
+	ok, _, err := Verify("not-the-real-password", hex.EncodeToString(hash), hex.EncodeToString(salt))
+	if err != nil {
+		t.Fatalf("Verify returned error: %v", err)
+	}
+	if ok {
+		t.Fatalf("Verify accepted the wrong legacy password")
+	}
+}
@@ -0,0 +1,60 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+// Package session provides the helpers routeAdminLogin and its OIDC/SVID
+// equivalents use to turn a freshly-signed JWT into a cookie-based admin
+// session: a stable lookup key for that token and a random double-submit
+// CSRF token to bind to it.
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JTI returns the unique identifier for token: its JWT "jti" claim, or a
+// stable SHA-256 fingerprint of the whole token if it doesn't carry one.
+// It reads the claims without verifying the signature. That's safe here
+// because JTI is only ever used as a lookup key into state's session
+// table, never as an authorization decision by itself - every caller
+// either just received token from net.CreateJwt or is about to have its
+// validity checked against that same table.
+func JTI(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("session: malformed JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("session: decoding JWT payload: %w", err)
+	}
+
+	var claims struct {
+		JTI string `json:"jti"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("session: parsing JWT claims: %w", err)
+	}
+	if claims.JTI != "" {
+		return claims.JTI, nil
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// NewCSRFToken generates a fresh random double-submit CSRF token.
+func NewCSRFToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("session: generating CSRF token: %w", err)
+	}
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,63 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+func TestJTI_ReturnsClaimWhenPresent(t *testing.T) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"jti":"abc-123"}`))
+	token := header + "." + payload + ".sig"
+
+	jti, err := JTI(token)
+	if err != nil {
+		t.Fatalf("JTI returned error: %v", err)
+	}
+	if jti != "abc-123" {
+		t.Fatalf("JTI = %q, want %q", jti, "abc-123")
+	}
+}
+
+func TestJTI_FallsBackToFingerprintWithoutClaim(t *testing.T) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`))
+	token := header + "." + payload + ".sig"
+
+	jti, err := JTI(token)
+	if err != nil {
+		t.Fatalf("JTI returned error: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	want := hex.EncodeToString(sum[:])
+	if jti != want {
+		t.Fatalf("JTI = %q, want fingerprint %q", jti, want)
+	}
+}
+
+func TestJTI_RejectsMalformedToken(t *testing.T) {
+	if _, err := JTI("not-a-jwt"); err == nil {
+		t.Fatalf("JTI accepted a malformed token")
+	}
+}
+
+func TestNewCSRFToken_GeneratesDistinctValuesEachCall(t *testing.T) {
+	t1, err := NewCSRFToken()
+	if err != nil {
+		t.Fatalf("NewCSRFToken returned error: %v", err)
+	}
+	t2, err := NewCSRFToken()
+	if err != nil {
+		t.Fatalf("NewCSRFToken returned error: %v", err)
+	}
+	if t1 == "" || t2 == "" || t1 == t2 {
+		t.Fatalf("NewCSRFToken produced t1=%q t2=%q, want distinct non-empty values", t1, t2)
+	}
+}
@@ -0,0 +1,128 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package svid
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+)
+
+// testLeafCert returns a throwaway self-signed certificate, standing in
+// for the leaf of an X.509-SVID chain - nothing under test inspects its
+// issuer, only its Subject and raw DER bytes.
+func testLeafCert(t *testing.T) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test-workload"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parsing certificate: %v", err)
+	}
+	return cert
+}
+
+func TestAllowed_ExactMatch(t *testing.T) {
+	allowedIDs := []string{"spiffe://example.org/admin-cli", "spiffe://example.org/other"}
+
+	if !allowed("spiffe://example.org/admin-cli", allowedIDs) {
+		t.Fatalf("allowed returned false for an exact match")
+	}
+}
+
+func TestAllowed_NoMatch(t *testing.T) {
+	allowedIDs := []string{"spiffe://example.org/admin-cli"}
+
+	if allowed("spiffe://example.org/intruder", allowedIDs) {
+		t.Fatalf("allowed returned true for a SPIFFE ID not in the allowlist")
+	}
+}
+
+func TestAllowed_EmptyAllowlistRejectsEverything(t *testing.T) {
+	if allowed("spiffe://example.org/admin-cli", nil) {
+		t.Fatalf("allowed returned true against an empty allowlist")
+	}
+}
+
+func TestAuthenticateRequest_RejectsNilTLS(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+
+	_, err = AuthenticateRequest(r, nil, []string{"spiffe://example.org/admin-cli"})
+	if err != ErrNoPeerCertificate {
+		t.Fatalf("err = %v, want %v", err, ErrNoPeerCertificate)
+	}
+}
+
+func TestAuthenticateRequest_RejectsEmptyPeerCertificates(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("building request: %v", err)
+	}
+	r.TLS = &tls.ConnectionState{}
+
+	_, err = AuthenticateRequest(r, nil, []string{"spiffe://example.org/admin-cli"})
+	if err != ErrNoPeerCertificate {
+		t.Fatalf("err = %v, want %v", err, ErrNoPeerCertificate)
+	}
+}
+
+func TestAuthenticate_RejectsSPIFFEIDNotInAllowlist(t *testing.T) {
+	id := spiffeid.RequireFromString("spiffe://example.org/intruder")
+	svid := &x509svid.SVID{ID: id}
+
+	_, err := authenticate(svid, testLeafCert(t), []string{"spiffe://example.org/admin-cli"})
+	if err != ErrNotAnAdmin {
+		t.Fatalf("err = %v, want %v", err, ErrNotAnAdmin)
+	}
+}
+
+func TestAuthenticate_AcceptsAllowedSPIFFEID(t *testing.T) {
+	id := spiffeid.RequireFromString("spiffe://example.org/admin-cli")
+	svid := &x509svid.SVID{ID: id}
+	leaf := testLeafCert(t)
+
+	caller, err := authenticate(svid, leaf, []string{"spiffe://example.org/admin-cli"})
+	if err != nil {
+		t.Fatalf("authenticate returned error: %v", err)
+	}
+	if caller.SPIFFEID != id.String() {
+		t.Fatalf("SPIFFEID = %q, want %q", caller.SPIFFEID, id.String())
+	}
+	if caller.Subject != leaf.Subject.String() {
+		t.Fatalf("Subject = %q, want %q", caller.Subject, leaf.Subject.String())
+	}
+	if caller.Sha256Fingerprint != fingerprint(leaf) {
+		t.Fatalf("Sha256Fingerprint = %q, want %q", caller.Sha256Fingerprint, fingerprint(leaf))
+	}
+}
@@ -0,0 +1,60 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package shamir
+
+// gfExp and gfLog are the standard exponentiation/logarithm tables for
+// GF(256) under the AES reduction polynomial (0x11B), generated once at
+// package init time from generator 0x03. They turn multiplication and
+// inversion into table lookups, which keeps Split/Combine fast even
+// though they operate byte-by-byte over the whole secret.
+var (
+	gfExp [512]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfLog[x] = byte(i)
+		x = gfMulNoTable(x, 3)
+	}
+	for i := 255; i < 512; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+// gfMulNoTable multiplies a and b in GF(256) via the shift-and-reduce
+// method; it is only used to bootstrap the gfExp/gfLog tables above.
+func gfMulNoTable(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		if b&1 != 0 {
+			p ^= a
+		}
+		hiBitSet := a & 0x80
+		a <<= 1
+		if hiBitSet != 0 {
+			a ^= 0x1B
+		}
+		b >>= 1
+	}
+	return p
+}
+
+// gfMul multiplies a and b in GF(256) using the precomputed log/exp
+// tables.
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// gfInv returns the multiplicative inverse of a in GF(256). a must be
+// non-zero; zero has no inverse.
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])]
+}
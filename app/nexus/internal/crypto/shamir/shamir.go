@@ -0,0 +1,212 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+// Package shamir implements Shamir's Secret Sharing over GF(256) so that the
+// SPIKE Nexus root key can be split across multiple SPIKE Keepers instead of
+// being handed to a single Keeper in full.
+//
+// Arithmetic is performed in GF(256) using the AES reduction polynomial
+// (x^8 + x^4 + x^3 + x + 1, i.e. 0x11B), the same field used by most
+// production Shamir implementations. Each share encodes the polynomial
+// index it was evaluated at, so shares can be collected and combined in
+// any order.
+package shamir
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrNotEnoughShares is returned by Combine when fewer than two shares are
+// supplied. Combine does not know the threshold the shares were created
+// with, so fewer shares than that threshold (but at least two) yield a
+// wrong secret rather than this error.
+var ErrNotEnoughShares = errors.New("shamir: not enough shares to reconstruct the secret")
+
+// ErrVersionMismatch is returned by Combine when the supplied shares were
+// not all generated by the same Split call (i.e. the same key generation).
+var ErrVersionMismatch = errors.New("shamir: shares belong to different key versions")
+
+// ErrDuplicateIndex is returned by Combine when two shares present the same
+// polynomial index; that is not enough information to interpolate.
+var ErrDuplicateIndex = errors.New("shamir: duplicate share index")
+
+// Share is a single Shamir share of a secret: the secret evaluated at
+// polynomial index Index, for the key generation identified by Version.
+type Share struct {
+	// Version identifies the key generation this share belongs to. Nexus
+	// increments Version every time it rotates the root key and re-splits
+	// it, so that shares from different rotations can never be mixed
+	// during recovery.
+	Version uint32
+	// Index is the non-zero x-coordinate this share was evaluated at.
+	Index byte
+	// Value is f(Index) for every byte of the secret, i.e. the share
+	// payload itself.
+	Value []byte
+}
+
+// Encode serializes a Share as `version(4 bytes, big endian) || index (1
+// byte) || value`, the wire format handed to a Keeper.
+func (s Share) Encode() []byte {
+	out := make([]byte, 4+1+len(s.Value))
+	binary.BigEndian.PutUint32(out[0:4], s.Version)
+	out[4] = s.Index
+	copy(out[5:], s.Value)
+	return out
+}
+
+// DecodeShare parses the wire format produced by Share.Encode.
+func DecodeShare(b []byte) (Share, error) {
+	if len(b) < 6 {
+		return Share{}, fmt.Errorf("shamir: encoded share too short: %d bytes", len(b))
+	}
+	return Share{
+		Version: binary.BigEndian.Uint32(b[0:4]),
+		Index:   b[4],
+		Value:   append([]byte(nil), b[5:]...),
+	}, nil
+}
+
+// MAC computes an HMAC-SHA256 tag over a share's wire encoding, keyed by
+// macKey. Nexus keeps macKey in memory for the lifetime of a key
+// generation and uses it to detect a corrupted or tampered share before
+// attempting to reconstruct the root key from it.
+func MAC(macKey []byte, s Share) []byte {
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(s.Encode())
+	return mac.Sum(nil)
+}
+
+// VerifyMAC reports whether tag is the correct HMAC-SHA256 tag for s under
+// macKey, using a constant-time comparison.
+func VerifyMAC(macKey []byte, s Share, tag []byte) bool {
+	return hmac.Equal(MAC(macKey, s), tag)
+}
+
+// Split divides secret into n shares such that any t of them (but no
+// fewer) are sufficient to reconstruct it. It returns the shares, a fresh
+// random macKey that the caller should retain to authenticate shares
+// during recovery (see MAC/VerifyMAC), and an error if the parameters are
+// invalid.
+//
+// For every byte of secret, Split builds a random polynomial of degree
+// t-1 over GF(256) with that byte as the constant term, and evaluates it
+// at n distinct, non-zero x-coordinates (1..n).
+func Split(secret []byte, n, t int, version uint32) ([]Share, []byte, error) {
+	if len(secret) == 0 {
+		return nil, nil, errors.New("shamir: secret is empty")
+	}
+	if t < 1 || n < 1 || t > n {
+		return nil, nil, fmt.Errorf("shamir: invalid threshold %d of %d shares", t, n)
+	}
+	if n > 255 {
+		return nil, nil, errors.New("shamir: cannot generate more than 255 shares")
+	}
+
+	values := make([][]byte, n)
+	for i := range values {
+		values[i] = make([]byte, len(secret))
+	}
+
+	coefficients := make([]byte, t-1)
+	for byteIdx, secretByte := range secret {
+		if _, err := rand.Read(coefficients); err != nil {
+			return nil, nil, fmt.Errorf("shamir: generating coefficients: %w", err)
+		}
+
+		for i := 0; i < n; i++ {
+			x := byte(i + 1)
+			values[i][byteIdx] = evalPolynomial(secretByte, coefficients, x)
+		}
+	}
+
+	shares := make([]Share, n)
+	for i := 0; i < n; i++ {
+		shares[i] = Share{Version: version, Index: byte(i + 1), Value: values[i]}
+	}
+
+	macKey := make([]byte, sha256.Size)
+	if _, err := rand.Read(macKey); err != nil {
+		return nil, nil, fmt.Errorf("shamir: generating MAC key: %w", err)
+	}
+
+	return shares, macKey, nil
+}
+
+// Combine reconstructs the secret from shares via Lagrange interpolation
+// at x=0. All shares must belong to the same Version and present distinct
+// Index values, and there must be at least two of them; callers are
+// responsible for ensuring at least the original threshold was met, since
+// Combine itself has no way to know what that threshold was.
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, ErrNotEnoughShares
+	}
+
+	version := shares[0].Version
+	secretLen := len(shares[0].Value)
+	seen := make(map[byte]bool, len(shares))
+	for _, s := range shares {
+		if s.Version != version {
+			return nil, ErrVersionMismatch
+		}
+		if s.Index == 0 {
+			return nil, errors.New("shamir: share index must be non-zero")
+		}
+		if seen[s.Index] {
+			return nil, ErrDuplicateIndex
+		}
+		seen[s.Index] = true
+		if len(s.Value) != secretLen {
+			return nil, errors.New("shamir: mismatched share lengths")
+		}
+	}
+
+	secret := make([]byte, secretLen)
+	for byteIdx := range secret {
+		secret[byteIdx] = interpolateAtZero(shares, byteIdx)
+	}
+	return secret, nil
+}
+
+// evalPolynomial evaluates, at x, the polynomial over GF(256) whose
+// constant term is secretByte and whose remaining coefficients (lowest
+// degree first) are given by coefficients.
+func evalPolynomial(secretByte byte, coefficients []byte, x byte) byte {
+	result := secretByte
+	xPow := byte(1)
+	for _, c := range coefficients {
+		xPow = gfMul(xPow, x)
+		result ^= gfMul(c, xPow)
+	}
+	return result
+}
+
+// interpolateAtZero evaluates, at x=0, the unique degree-(len(shares)-1)
+// polynomial that passes through each share's (Index, Value[byteIdx])
+// point, using the standard Lagrange interpolation formula specialized to
+// x=0 (GF(256) addition is XOR, so the sum becomes an XOR-accumulation).
+func interpolateAtZero(shares []Share, byteIdx int) byte {
+	var result byte
+	for i, si := range shares {
+		numerator := byte(1)
+		denominator := byte(1)
+		for j, sj := range shares {
+			if i == j {
+				continue
+			}
+			// (0 - x_j) == x_j in GF(256) since subtraction is XOR.
+			numerator = gfMul(numerator, sj.Index)
+			denominator = gfMul(denominator, si.Index^sj.Index)
+		}
+		term := gfMul(si.Value[byteIdx], gfMul(numerator, gfInv(denominator)))
+		result ^= term
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombine_ThresholdSharesReconstruct(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	shares, _, err := Split(secret, 5, 3, 1)
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("expected 5 shares, got %d", len(shares))
+	}
+
+	got, err := Combine(shares[:3])
+	if err != nil {
+		t.Fatalf("Combine returned error: %v", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Fatalf("Combine(t shares) = %x, want %x", got, secret)
+	}
+
+	got, err = Combine([]Share{shares[0], shares[2], shares[4]})
+	if err != nil {
+		t.Fatalf("Combine returned error: %v", err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Fatalf("Combine(arbitrary t shares) = %x, want %x", got, secret)
+	}
+}
+
+func TestCombine_BelowThresholdDoesNotReconstruct(t *testing.T) {
+	secret := []byte("top-secret-root-key-material!!!")
+	shares, _, err := Split(secret, 5, 3, 1)
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+
+	got, err := Combine(shares[:2])
+	if err != nil {
+		t.Fatalf("Combine returned error: %v", err)
+	}
+	if bytes.Equal(got, secret) {
+		t.Fatalf("Combine(t-1 shares) unexpectedly reconstructed the secret")
+	}
+}
+
+func TestCombine_RejectsMixedVersions(t *testing.T) {
+	secret := []byte("another-secret!!")
+	sharesV1, _, err := Split(secret, 3, 2, 1)
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+	sharesV2, _, err := Split(secret, 3, 2, 2)
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+
+	_, err = Combine([]Share{sharesV1[0], sharesV2[1]})
+	if err != ErrVersionMismatch {
+		t.Fatalf("Combine(mixed versions) error = %v, want %v", err, ErrVersionMismatch)
+	}
+}
+
+func TestCombine_RejectsDuplicateIndex(t *testing.T) {
+	secret := []byte("yet-another-secret")
+	shares, _, err := Split(secret, 3, 2, 1)
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+
+	_, err = Combine([]Share{shares[0], shares[0]})
+	if err != ErrDuplicateIndex {
+		t.Fatalf("Combine(duplicate index) error = %v, want %v", err, ErrDuplicateIndex)
+	}
+}
+
+func TestMAC_DetectsCorruptedShare(t *testing.T) {
+	secret := []byte("root-key-bytes-for-mac-testing!")
+	shares, macKey, err := Split(secret, 3, 2, 7)
+	if err != nil {
+		t.Fatalf("Split returned error: %v", err)
+	}
+
+	tag := MAC(macKey, shares[0])
+	if !VerifyMAC(macKey, shares[0], tag) {
+		t.Fatalf("VerifyMAC rejected a valid share/tag pair")
+	}
+
+	corrupted := shares[0]
+	corrupted.Value = append([]byte(nil), corrupted.Value...)
+	corrupted.Value[0] ^= 0xFF
+	if VerifyMAC(macKey, corrupted, tag) {
+		t.Fatalf("VerifyMAC accepted a corrupted share")
+	}
+}
+
+func TestEncodeDecodeShare_RoundTrips(t *testing.T) {
+	s := Share{Version: 42, Index: 7, Value: []byte{1, 2, 3, 4}}
+	decoded, err := DecodeShare(s.Encode())
+	if err != nil {
+		t.Fatalf("DecodeShare returned error: %v", err)
+	}
+	if decoded.Version != s.Version || decoded.Index != s.Index || !bytes.Equal(decoded.Value, s.Value) {
+		t.Fatalf("DecodeShare(Encode(s)) = %+v, want %+v", decoded, s)
+	}
+}
@@ -0,0 +1,65 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+// OidcIssuer returns the OIDC provider's issuer URL (used for discovery
+// of the authorization, token and JWKS endpoints), read from
+// SPIKE_NEXUS_OIDC_ISSUER. An empty result means OIDC login is disabled.
+func OidcIssuer() string {
+	return os.Getenv("SPIKE_NEXUS_OIDC_ISSUER")
+}
+
+// OidcClientID returns the OAuth2 client id Nexus presents to the OIDC
+// provider, read from SPIKE_NEXUS_OIDC_CLIENT_ID.
+func OidcClientID() string {
+	return os.Getenv("SPIKE_NEXUS_OIDC_CLIENT_ID")
+}
+
+// OidcClientSecret returns the OAuth2 client secret, read from
+// SPIKE_NEXUS_OIDC_CLIENT_SECRET. It is empty for public clients that
+// rely solely on PKCE.
+func OidcClientSecret() string {
+	return os.Getenv("SPIKE_NEXUS_OIDC_CLIENT_SECRET")
+}
+
+// OidcRedirectURL returns the callback URL Nexus registered with the OIDC
+// provider, read from SPIKE_NEXUS_OIDC_REDIRECT_URL.
+func OidcRedirectURL() string {
+	return os.Getenv("SPIKE_NEXUS_OIDC_REDIRECT_URL")
+}
+
+// OidcClaim returns the ID token claim Nexus maps to admin authorization
+// (e.g. "email" or "groups"), read from SPIKE_NEXUS_OIDC_CLAIM. Defaults
+// to "email".
+func OidcClaim() string {
+	if v := os.Getenv("SPIKE_NEXUS_OIDC_CLAIM"); v != "" {
+		return v
+	}
+	return "email"
+}
+
+// OidcAllowedClaimValues returns the set of claim values (from
+// OidcClaim) that are authorized to log in as admin, read from the
+// comma-separated SPIKE_NEXUS_OIDC_ALLOWED_VALUES.
+func OidcAllowedClaimValues() []string {
+	raw := os.Getenv("SPIKE_NEXUS_OIDC_ALLOWED_VALUES")
+	if raw == "" {
+		return nil
+	}
+
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
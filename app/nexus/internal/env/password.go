@@ -0,0 +1,43 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package env
+
+const (
+	defaultArgon2Time        = 3
+	defaultArgon2MemoryKiB   = 64 * 1024 // 64 MiB
+	defaultArgon2Parallelism = 2
+	defaultArgon2KeyLength   = 32
+	defaultArgon2SaltLength  = 16
+)
+
+// Argon2Time returns the Argon2id time (iteration) cost, read from
+// SPIKE_NEXUS_ARGON2_TIME. Defaults to 3.
+func Argon2Time() uint32 {
+	return uint32(envIntOrDefault("SPIKE_NEXUS_ARGON2_TIME", defaultArgon2Time))
+}
+
+// Argon2MemoryKiB returns the Argon2id memory cost in KiB, read from
+// SPIKE_NEXUS_ARGON2_MEMORY_KIB. Defaults to 65536 (64 MiB).
+func Argon2MemoryKiB() uint32 {
+	return uint32(envIntOrDefault("SPIKE_NEXUS_ARGON2_MEMORY_KIB", defaultArgon2MemoryKiB))
+}
+
+// Argon2Parallelism returns the Argon2id parallelism factor, read from
+// SPIKE_NEXUS_ARGON2_PARALLELISM. Defaults to 2.
+func Argon2Parallelism() uint8 {
+	return uint8(envIntOrDefault("SPIKE_NEXUS_ARGON2_PARALLELISM", defaultArgon2Parallelism))
+}
+
+// Argon2KeyLength returns the derived key length in bytes, read from
+// SPIKE_NEXUS_ARGON2_KEY_LENGTH. Defaults to 32.
+func Argon2KeyLength() uint32 {
+	return uint32(envIntOrDefault("SPIKE_NEXUS_ARGON2_KEY_LENGTH", defaultArgon2KeyLength))
+}
+
+// Argon2SaltLength returns the random salt length in bytes, read from
+// SPIKE_NEXUS_ARGON2_SALT_LENGTH. Defaults to 16.
+func Argon2SaltLength() uint32 {
+	return uint32(envIntOrDefault("SPIKE_NEXUS_ARGON2_SALT_LENGTH", defaultArgon2SaltLength))
+}
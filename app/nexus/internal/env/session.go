@@ -0,0 +1,46 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package env
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultSessionTTL = 15 * time.Minute
+
+// SessionCookieName is the name of the cookie routeAdminLogin and its
+// OIDC/SVID equivalents set on successful login. It isn't configurable:
+// clients need a fixed, well-known name to look for.
+const SessionCookieName = "spike_session"
+
+// CSRFHeaderName is the header the double-submit CSRF token travels in,
+// both when a login route returns it and when a state-mutating request
+// must present it back.
+const CSRFHeaderName = "X-CSRF-Token"
+
+// SessionTTL returns how long a cookie session, and the JWT it carries,
+// stays valid, read from SPIKE_NEXUS_SESSION_TTL_SECONDS. Defaults to 15
+// minutes.
+func SessionTTL() time.Duration {
+	raw := os.Getenv("SPIKE_NEXUS_SESSION_TTL_SECONDS")
+	if raw == "" {
+		return defaultSessionTTL
+	}
+
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 {
+		return defaultSessionTTL
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// SessionDomain returns the Domain attribute to set on the session
+// cookie, read from SPIKE_NEXUS_SESSION_DOMAIN. Empty by default, which
+// makes the cookie host-only.
+func SessionDomain() string {
+	return os.Getenv("SPIKE_NEXUS_SESSION_DOMAIN")
+}
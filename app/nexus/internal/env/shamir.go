@@ -0,0 +1,82 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package env
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultShamirThreshold = 2
+	defaultShamirShares    = 3
+)
+
+// KeeperPeers returns the SPIFFE IDs of the SPIKE Keepers that Nexus
+// should split the root key across, read from the comma-separated
+// SPIKE_NEXUS_KEEPER_PEERS environment variable. There is no sane
+// built-in default: without peers configured, Nexus has nowhere to send
+// shares.
+func KeeperPeers() []string {
+	raw := os.Getenv("SPIKE_NEXUS_KEEPER_PEERS")
+	if raw == "" {
+		return nil
+	}
+
+	var peers []string
+	for _, p := range strings.Split(raw, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			peers = append(peers, p)
+		}
+	}
+	return peers
+}
+
+// ShamirThreshold returns the minimum number of Keeper shares required to
+// reconstruct the root key, read from SPIKE_NEXUS_SHAMIR_THRESHOLD.
+// Defaults to 2.
+func ShamirThreshold() int {
+	return envIntOrDefault("SPIKE_NEXUS_SHAMIR_THRESHOLD", defaultShamirThreshold)
+}
+
+// ShamirShares returns the total number of shares the root key is split
+// into, read from SPIKE_NEXUS_SHAMIR_SHARES. Defaults to 3.
+func ShamirShares() int {
+	return envIntOrDefault("SPIKE_NEXUS_SHAMIR_SHARES", defaultShamirShares)
+}
+
+// KeeperShardURL returns the URL Nexus should call to push or fetch a
+// Shamir share for the given Keeper SPIFFE ID, read from
+// SPIKE_NEXUS_KEEPER_URL_<n> where <n> is the 1-based position of that
+// SPIFFE ID in KeeperPeers. Keeper addressing mirrors the ordering of the
+// peers list so the two stay in lockstep.
+func KeeperShardURL(keeperSPIFFEID string) string {
+	peers := KeeperPeers()
+	for i, p := range peers {
+		if p != keeperSPIFFEID {
+			continue
+		}
+		if url := os.Getenv(fmt.Sprintf("SPIKE_NEXUS_KEEPER_URL_%d", i+1)); url != "" {
+			return url + "/v1/store/shard"
+		}
+	}
+	return ""
+}
+
+func envIntOrDefault(name string, fallback int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return fallback
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,28 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package env
+
+import "os"
+
+const defaultStoreDriver = "memory"
+
+// StoreDriver returns the name of the store.Store driver Nexus persists
+// the root key, admin credentials, and admin token to, read from
+// SPIKE_NEXUS_STORE_DRIVER. Defaults to "memory", matching the
+// process-lifetime-only behavior Nexus had before a pluggable backend
+// existed.
+func StoreDriver() string {
+	if v := os.Getenv("SPIKE_NEXUS_STORE_DRIVER"); v != "" {
+		return v
+	}
+	return defaultStoreDriver
+}
+
+// StoreDSN returns the driver-specific connection string read from
+// SPIKE_NEXUS_STORE_DSN: a file path for bbolt/sqlite, a data directory
+// for badger, a DSN for postgres. Ignored by the memory driver.
+func StoreDSN() string {
+	return os.Getenv("SPIKE_NEXUS_STORE_DSN")
+}
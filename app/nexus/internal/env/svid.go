@@ -0,0 +1,30 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+// AdminSPIFFEIDs returns the SPIFFE IDs that are authorized to log in as
+// admin via their X.509-SVID, read from the comma-separated
+// SPIKE_NEXUS_ADMIN_SPIFFE_IDS environment variable. An empty result
+// disables SVID-based admin login entirely.
+func AdminSPIFFEIDs() []string {
+	raw := os.Getenv("SPIKE_NEXUS_ADMIN_SPIFFE_IDS")
+	if raw == "" {
+		return nil
+	}
+
+	var ids []string
+	for _, id := range strings.Split(raw, ",") {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,131 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package net
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+
+	"github.com/spiffe/spike/app/nexus/internal/crypto/shamir"
+	"github.com/spiffe/spike/app/nexus/internal/env"
+	"github.com/spiffe/spike/internal/entity/v1/reqres"
+)
+
+// UpdateShard pushes a single Keeper's Shamir share of the root key over
+// mTLS, using the same workload X.509 source that UpdateCache uses to
+// talk to Keepers. It replaces UpdateCache on the Shamir-sharing path:
+// each Keeper only ever sees its own share, never the full root key.
+func UpdateShard(
+	source *workloadapi.X509Source, keeperSPIFFEID string,
+	encodedShare []byte, mac string,
+) error {
+	share, err := shamir.DecodeShare(encodedShare)
+	if err != nil {
+		return fmt.Errorf("UpdateShard: invalid share: %w", err)
+	}
+
+	req := reqres.ShardUpdateRequest{
+		Version: share.Version,
+		Index:   share.Index,
+		Share:   hex.EncodeToString(share.Value),
+		MAC:     mac,
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("UpdateShard: marshaling request: %w", err)
+	}
+
+	id, err := spiffeid.FromString(keeperSPIFFEID)
+	if err != nil {
+		return fmt.Errorf("UpdateShard: invalid Keeper SPIFFE ID %q: %w", keeperSPIFFEID, err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsconfig.MTLSClientConfig(source, source, tlsconfig.AuthorizeID(id)),
+		},
+	}
+
+	httpReq, err := http.NewRequest(
+		http.MethodPost, env.KeeperShardURL(keeperSPIFFEID), bytes.NewReader(body),
+	)
+	if err != nil {
+		return fmt.Errorf("UpdateShard: building request: %w", err)
+	}
+
+	res, err := client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("UpdateShard: calling Keeper: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("UpdateShard: Keeper returned %d: %s", res.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// FetchShard retrieves a single Keeper's Shamir share during recovery,
+// over the same mTLS connection UpdateShard uses to push shares.
+func FetchShard(
+	source *workloadapi.X509Source, keeperSPIFFEID string,
+) (reqres.ShardShowResponse, error) {
+	var out reqres.ShardShowResponse
+
+	id, err := spiffeid.FromString(keeperSPIFFEID)
+	if err != nil {
+		return out, fmt.Errorf("FetchShard: invalid Keeper SPIFFE ID %q: %w", keeperSPIFFEID, err)
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsconfig.MTLSClientConfig(source, source, tlsconfig.AuthorizeID(id)),
+		},
+	}
+
+	body, err := json.Marshal(reqres.ShardShowRequest{})
+	if err != nil {
+		return out, fmt.Errorf("FetchShard: marshaling request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(
+		http.MethodPost, env.KeeperShardURL(keeperSPIFFEID), bytes.NewReader(body),
+	)
+	if err != nil {
+		return out, fmt.Errorf("FetchShard: building request: %w", err)
+	}
+
+	res, err := client.Do(httpReq)
+	if err != nil {
+		return out, fmt.Errorf("FetchShard: calling Keeper: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	respBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return out, fmt.Errorf("FetchShard: reading response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return out, fmt.Errorf("FetchShard: Keeper returned %d: %s", res.StatusCode, string(respBody))
+	}
+
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return out, fmt.Errorf("FetchShard: unmarshaling response: %w", err)
+	}
+
+	return out, nil
+}
@@ -6,17 +6,21 @@ package poll
 
 import (
 	"context"
+	"encoding/hex"
 	"log"
 	"time"
 
 	"github.com/spiffe/go-spiffe/v2/workloadapi"
 
+	"github.com/spiffe/spike/app/nexus/internal/crypto/shamir"
+	"github.com/spiffe/spike/app/nexus/internal/env"
 	"github.com/spiffe/spike/app/nexus/internal/net"
 	"github.com/spiffe/spike/app/nexus/internal/state"
 )
 
-// Tick continuously updates SPIKE Keeper, sending the root key to be backed up
-// in memory.
+// Tick continuously updates SPIKE Keeper, splitting the root key into
+// Shamir shares and sending a distinct share to each configured Keeper
+// instead of shipping the full key to a single one.
 //
 // It runs until the provided context is cancelled.
 //
@@ -53,16 +57,51 @@ func Tick(ctx context.Context,
 			if key == "" {
 				continue
 			}
-			err := net.UpdateCache(source, key)
+
+			peers := env.KeeperPeers()
+			if len(peers) == 0 {
+				log.Println("poll.Tick: no Keeper peers configured; skipping share distribution")
+				continue
+			}
+
+			if want := env.ShamirShares(); want != len(peers) {
+				log.Printf(
+					"poll.Tick: SPIKE_NEXUS_SHAMIR_SHARES=%d does not match %d configured Keeper peers; splitting into %d shares, one per peer\n",
+					want, len(peers), len(peers),
+				)
+			}
+
+			version := state.RootKeyVersion()
+			shares, macKey, err := shamir.Split(
+				[]byte(key), len(peers), env.ShamirThreshold(), version,
+			)
 			if err != nil {
-				log.Println("")
-				log.Printf("Unable to update the cache: %v\n", err)
-				log.Println("Make sure SPIKE Keeper is up and running")
-				log.Println("")
+				log.Printf("poll.Tick: Unable to split root key: %v\n", err)
+				continue
+			}
+			state.SetShamirMACKey(version, macKey)
+
+			failures := 0
+			for i, peer := range peers {
+				share := shares[i]
+				err := net.UpdateShard(source, peer, share.Encode(), hex.EncodeToString(
+					shamir.MAC(macKey, share),
+				))
+				if err != nil {
+					failures++
+					log.Println("")
+					log.Printf("Unable to update Keeper %s: %v\n", peer, err)
+					log.Println("")
+					continue
+				}
+			}
+
+			if failures > 0 {
+				log.Println("Make sure every SPIKE Keeper is up and running")
 				continue
 			}
 
-			log.Println("Successfully updated the cache")
+			log.Println("Successfully distributed root key shares to all Keepers")
 		case <-ctx.Done():
 			return
 		}
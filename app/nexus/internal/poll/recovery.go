@@ -0,0 +1,99 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package poll
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+
+	"github.com/spiffe/spike/app/nexus/internal/crypto/shamir"
+	"github.com/spiffe/spike/app/nexus/internal/env"
+	"github.com/spiffe/spike/app/nexus/internal/net"
+	"github.com/spiffe/spike/app/nexus/internal/state"
+)
+
+// ErrInsufficientShares is returned by Recover when fewer than the
+// configured threshold of verified, same-version shares could be
+// collected from the configured Keeper peers.
+var ErrInsufficientShares = errors.New("poll: fewer than the required threshold of shares were recovered")
+
+// Recover reconstructs the root key by fetching Shamir shares from the
+// configured Keeper peers over mTLS and Lagrange-interpolating them at
+// x=0. It is the counterpart to Tick's share distribution, used when
+// Nexus restarts and needs to repopulate state.RootKey from the Keepers
+// rather than the other way around.
+//
+// Recover only accepts shares that pass their HMAC check against the MAC
+// key recorded for their version (see state.SetShamirMACKey), and refuses
+// to reconstruct the key unless at least the configured threshold of
+// same-version, verified shares were collected. It combines the version
+// with the most verified shares, whether or not it is the newest; shares
+// of every other version are silently discarded rather than mixed in.
+func Recover(source *workloadapi.X509Source) (string, error) {
+	peers := env.KeeperPeers()
+	threshold := env.ShamirThreshold()
+
+	byVersion := make(map[uint32][]shamir.Share)
+
+	for _, peer := range peers {
+		resp, err := net.FetchShard(source, peer)
+		if err != nil {
+			log.Printf("poll.Recover: unable to fetch share from %s: %v\n", peer, err)
+			continue
+		}
+
+		shareBytes, err := hex.DecodeString(resp.Share)
+		if err != nil {
+			log.Printf("poll.Recover: %s returned an undecodable share: %v\n", peer, err)
+			continue
+		}
+		macBytes, err := hex.DecodeString(resp.MAC)
+		if err != nil {
+			log.Printf("poll.Recover: %s returned an undecodable MAC: %v\n", peer, err)
+			continue
+		}
+
+		share := shamir.Share{Version: resp.Version, Index: resp.Index, Value: shareBytes}
+
+		macKey, ok := state.ShamirMACKey(resp.Version)
+		if !ok {
+			log.Printf("poll.Recover: no MAC key recorded for version %d from %s; discarding\n", resp.Version, peer)
+			continue
+		}
+		if !shamir.VerifyMAC(macKey, share, macBytes) {
+			log.Printf("poll.Recover: share from %s failed its HMAC check; discarding\n", peer)
+			continue
+		}
+
+		byVersion[resp.Version] = append(byVersion[resp.Version], share)
+	}
+
+	var bestVersion uint32
+	var bestShares []shamir.Share
+	for version, shares := range byVersion {
+		if len(shares) > len(bestShares) {
+			bestVersion = version
+			bestShares = shares
+		}
+	}
+
+	if len(bestShares) < threshold {
+		return "", fmt.Errorf(
+			"%w: got %d verified shares of version %d, need %d",
+			ErrInsufficientShares, len(bestShares), bestVersion, threshold,
+		)
+	}
+
+	secret, err := shamir.Combine(bestShares[:threshold])
+	if err != nil {
+		return "", fmt.Errorf("poll.Recover: combining shares: %w", err)
+	}
+
+	return string(secret), nil
+}
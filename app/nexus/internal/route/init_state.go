@@ -5,25 +5,24 @@
 package route
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"log"
 
-	"github.com/spiffe/spike/app/nexus/internal/env"
+	"github.com/spiffe/spike/app/nexus/internal/auth/password"
 	"github.com/spiffe/spike/app/nexus/internal/state"
-	"golang.org/x/crypto/pbkdf2"
 )
 
-func updateStateForInit(password string, adminTokenBytes, salt []byte) {
-	iterationCount := env.Pbkdf2IterationCount()
-	hashLength := env.ShaHashLength()
-	passwordHash := pbkdf2.Key(
-		[]byte(password), salt,
-		iterationCount, hashLength, sha256.New,
-	)
+// updateStateForInit hashes rawPassword with Argon2id and stores the
+// resulting self-describing PHC record as the admin credential. salt is
+// accepted for backward compatibility with existing callers but is no
+// longer used: Hash generates its own random salt per the parameters in
+// env.Argon2*.
+func updateStateForInit(rawPassword string, adminTokenBytes, salt []byte) {
+	encoded, err := password.Hash(rawPassword)
+	if err != nil {
+		log.Printf("updateStateForInit: Problem hashing admin password: %v\n", err)
+		return
+	}
 
 	state.SetAdminToken("spike." + string(adminTokenBytes))
-	state.SetAdminCredentials(
-		hex.EncodeToString(passwordHash),
-		hex.EncodeToString(salt),
-	)
+	state.SetAdminCredentials(encoded, "")
 }
\ No newline at end of file
@@ -5,37 +5,43 @@
 package route
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"net/http"
 
-	"golang.org/x/crypto/pbkdf2"
-
+	"github.com/spiffe/spike/app/nexus/internal/auth/password"
 	"github.com/spiffe/spike/app/nexus/internal/state"
 	"github.com/spiffe/spike/internal/entity/v1/reqres"
 	"github.com/spiffe/spike/internal/log"
 	"github.com/spiffe/spike/internal/net"
 )
 
-// routeAdminLogin handles HTTP requests for administrator authentication using PBKDF2-SHA256
-// password hashing. It validates the provided password against stored credentials and issues
-// a JWT token upon successful authentication.
+// routeAdminLogin handles HTTP requests for administrator authentication.
+// It validates the provided password against stored credentials and
+// issues a JWT token upon successful authentication.
+//
+// Credentials are verified via password.Verify, which accepts both the
+// current Argon2id PHC-encoded records and the legacy bare-hex
+// PBKDF2-SHA256 records that routeAdminLogin used to produce. When a
+// login succeeds against a legacy record, routeAdminLogin transparently
+// rehashes the submitted password with Argon2id and persists it, the
+// same pattern modern web login systems use to migrate hashes in place.
 //
-// The function implements the following security measures:
-//   - PBKDF2-SHA256 password hashing with 600,000 iterations (OWASP recommended minimum)
-//   - Constant-time password comparison using crypto/hmac.Equal
-//   - Salted password hashing
-//   - JWT token-based authentication
+// On success, issueSession also registers the token as a cookie session
+// and sets it as the spike_session cookie, alongside returning it in the
+// response body: a browser-based client can rely on the cookie and
+// ignore the body, while a non-browser client can keep doing what it
+// already does. The X-CSRF-Token response header carries the
+// double-submit token that RequireCSRF will expect back on any
+// state-mutating request made over the cookie.
 //
 // Authentication Process:
 //  1. Reads and validates the request body containing the password
-//  2. Retrieves stored admin credentials (password hash and salt)
-//  3. Decodes the stored salt and password hash from hex format
-//  4. Generates a new hash from the provided password using PBKDF2
-//  5. Performs constant-time comparison of password hashes
-//  6. Issues a signed JWT token upon successful authentication
+//  2. Retrieves the stored admin credential from state.AdminCredentials
+//  3. Verifies the password against it via password.Verify
+//  4. If verification succeeded against a legacy record, rehashes with
+//     Argon2id and updates state.AdminCredentials
+//  5. Issues a signed JWT token upon successful authentication and
+//     registers it as a cookie session
 //
 // Parameters:
 //   - w: http.ResponseWriter to write the HTTP response
@@ -45,7 +51,7 @@ import (
 // Returns:
 //   - error: nil if authentication succeeds, or an error if:
 //   - Request body cannot be read or parsed
-//   - Salt or password hash cannot be decoded
+//   - The stored credential cannot be parsed
 //   - Password is invalid
 //   - Admin token is not set
 //   - JWT token cannot be signed
@@ -74,11 +80,6 @@ import (
 //	{
 //	  "err": "error_code"
 //	}
-//
-// Security Notes:
-//   - Uses PBKDF2-SHA256 with 600,000 iterations for password hashing
-//   - Output hash length is 32 bytes (256 bits)
-//   - Implements constant-time comparison to prevent timing attacks
 func routeAdminLogin(
 	w http.ResponseWriter, r *http.Request, audit *log.AuditEntry,
 ) error {
@@ -101,15 +102,13 @@ func routeAdminLogin(
 		return errors.New("failed to parse request body")
 	}
 
-	password := request.Password
+	rawPassword := request.Password
 	creds := state.AdminCredentials()
-	passwordHash := creds.PasswordHash
-	salt := creds.Salt
 
-	s, err := hex.DecodeString(salt)
+	ok, needsRehash, err := password.Verify(rawPassword, creds.PasswordHash, creds.Salt)
 	if err != nil {
 		log.Log().Error("routeAdminLogin",
-			"msg", "Problem decoding salt",
+			"msg", "Problem verifying password",
 			"err", err.Error())
 
 		body := net.MarshalBody(reqres.AdminLoginResponse{
@@ -121,37 +120,10 @@ func routeAdminLogin(
 
 		net.Respond(http.StatusInternalServerError, body, w)
 		log.Log().Info("routeAdminLogin", "msg", "unauthorized")
-		return errors.New("failed to decode salt")
+		return errors.New("failed to verify password")
 	}
 
-	// TODO: duplication.
-	// TODO: make this configurable.
-	iterationCount := 600_000 // Minimum OWASP recommendation for PBKDF2-SHA256
-	hashLength := 32          // 256 bits output
-
-	ph := pbkdf2.Key(
-		[]byte(password), s,
-		iterationCount, hashLength, sha256.New,
-	)
-
-	b, err := hex.DecodeString(passwordHash)
-	if err != nil {
-		log.Log().Error("routeAdminLogin",
-			"msg", "Problem decoding password hash",
-			"err", err.Error())
-
-		responseBody := net.MarshalBody(reqres.AdminLoginResponse{
-			Err: reqres.ErrServerFault}, w)
-		if responseBody == nil {
-			return errors.New("failed to marshal response body")
-		}
-
-		net.Respond(http.StatusInternalServerError, responseBody, w)
-		log.Log().Info("routeAdminLogin", "msg", "OK")
-		return errors.New("failed to decode password hash")
-	}
-
-	if !hmac.Equal(ph, b) {
+	if !ok {
 		log.Log().Info("routeAdminLogin", "msg", "Invalid password")
 
 		responseBody := net.MarshalBody(reqres.AdminLoginResponse{
@@ -165,6 +137,18 @@ func routeAdminLogin(
 		return errors.New("invalid password")
 	}
 
+	if needsRehash {
+		rehashed, err := password.Hash(rawPassword)
+		if err != nil {
+			log.Log().Error("routeAdminLogin",
+				"msg", "Problem rehashing legacy password",
+				"err", err.Error())
+		} else {
+			state.SetAdminCredentials(rehashed, "")
+			log.Log().Info("routeAdminLogin", "msg", "migrated legacy credential to Argon2id")
+		}
+	}
+
 	adminToken := state.AdminToken()
 	if adminToken == "" {
 		log.Log().Error("routeAdminLogin", "msg", "Admin token not set")
@@ -185,6 +169,18 @@ func routeAdminLogin(
 		return errors.New("failed to sign token")
 	}
 
+	if err := issueSession(w, signedToken); err != nil {
+		log.Log().Error("routeAdminLogin", "msg", "Problem issuing session", "err", err.Error())
+
+		responseBody := net.MarshalBody(reqres.AdminLoginResponse{
+			Err: reqres.ErrServerFault}, w)
+		if responseBody == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, responseBody, w)
+		return err
+	}
+
 	responseBody := net.MarshalBody(reqres.AdminLoginResponse{
 		Token: signedToken,
 	}, w)
@@ -0,0 +1,178 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/spiffe/spike/app/nexus/internal/auth/oidc"
+	"github.com/spiffe/spike/app/nexus/internal/env"
+	"github.com/spiffe/spike/app/nexus/internal/state"
+	"github.com/spiffe/spike/internal/entity/v1/reqres"
+	"github.com/spiffe/spike/internal/log"
+	"github.com/spiffe/spike/internal/net"
+)
+
+// routeAdminCallback completes the authorization code + PKCE flow that
+// routeAdminLoginOIDC started. It exchanges the provider's code for
+// tokens, verifies the ID token's signature against the provider's JWKS
+// and validates `iss`, `aud`, `exp` and `nonce`, maps the configured
+// claim (env.OidcClaim) to admin authorization, and on success issues the
+// same signed JWT that routeAdminLogin returns for password logins,
+// registered as the same kind of cookie session via issueSession.
+//
+// Request Body:
+//
+//	{
+//	  "code": "...",
+//	  "state": "..."
+//	}
+//
+// Response Body on Success:
+//
+//	{
+//	  "token": "signed_jwt_token"
+//	}
+func routeAdminCallback(
+	w http.ResponseWriter, r *http.Request, audit *log.AuditEntry,
+) error {
+	log.Log().Info("routeAdminCallback", "method", r.Method, "path", r.URL.Path,
+		"query", r.URL.RawQuery)
+	audit.Action = "login-oidc-callback"
+
+	requestBody := net.ReadRequestBody(r, w)
+	if requestBody == nil {
+		return errors.New("failed to read request body")
+	}
+
+	request := net.HandleRequest[
+		reqres.AdminCallbackRequest, reqres.AdminCallbackResponse](
+		requestBody, w,
+		reqres.AdminCallbackResponse{Err: reqres.ErrBadInput},
+	)
+	if request == nil {
+		return errors.New("failed to parse request body")
+	}
+
+	pending, ok := state.TakePendingOIDCLogin(request.State)
+	if !ok {
+		log.Log().Info("routeAdminCallback", "msg", "unknown or expired state")
+
+		body := net.MarshalBody(reqres.AdminCallbackResponse{
+			Err: reqres.ErrUnauthorized,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusUnauthorized, body, w)
+		return errors.New("unknown or expired oidc state")
+	}
+
+	issuer := env.OidcIssuer()
+	discovery, err := oidc.Discover(issuer)
+	if err != nil {
+		log.Log().Error("routeAdminCallback", "msg", "Problem discovering OIDC provider",
+			"err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminCallbackResponse{
+			Err: reqres.ErrServerFault,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, body, w)
+		return err
+	}
+
+	tokens, err := oidc.ExchangeCode(
+		discovery, env.OidcClientID(), env.OidcClientSecret(),
+		env.OidcRedirectURL(), request.Code, pending.CodeVerifier,
+	)
+	if err != nil {
+		log.Log().Error("routeAdminCallback", "msg", "Problem exchanging code",
+			"err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminCallbackResponse{
+			Err: reqres.ErrUnauthorized,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusUnauthorized, body, w)
+		return err
+	}
+
+	claims, err := oidc.VerifyIDToken(discovery, tokens.IDToken, env.OidcClientID(), pending.Nonce)
+	if err != nil {
+		log.Log().Error("routeAdminCallback", "msg", "Problem verifying id token",
+			"err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminCallbackResponse{
+			Err: reqres.ErrUnauthorized,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusUnauthorized, body, w)
+		return err
+	}
+
+	if !oidc.ClaimAuthorized(claims, env.OidcClaim(), env.OidcAllowedClaimValues()) {
+		log.Log().Info("routeAdminCallback", "msg", "claim did not authorize admin access")
+
+		body := net.MarshalBody(reqres.AdminCallbackResponse{
+			Err: reqres.ErrUnauthorized,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusUnauthorized, body, w)
+		return errors.New("claim did not authorize admin access")
+	}
+
+	adminToken := state.AdminToken()
+	if adminToken == "" {
+		log.Log().Error("routeAdminCallback", "msg", "Admin token not set")
+
+		body := net.MarshalBody(reqres.AdminCallbackResponse{
+			Err: reqres.ErrServerFault,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, body, w)
+		return errors.New("admin token not set")
+	}
+
+	signedToken := net.CreateJwt(adminToken, w)
+	if signedToken == "" {
+		return errors.New("failed to sign token")
+	}
+
+	if err := issueSession(w, signedToken); err != nil {
+		log.Log().Error("routeAdminCallback", "msg", "Problem issuing session", "err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminCallbackResponse{
+			Err: reqres.ErrServerFault,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, body, w)
+		return err
+	}
+
+	responseBody := net.MarshalBody(reqres.AdminCallbackResponse{
+		Token: signedToken,
+	}, w)
+	if responseBody == nil {
+		return errors.New("failed to marshal response body")
+	}
+
+	net.Respond(http.StatusOK, responseBody, w)
+	log.Log().Info("routeAdminCallback", "msg", "authorized")
+	return nil
+}
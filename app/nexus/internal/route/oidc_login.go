@@ -0,0 +1,118 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/spiffe/spike/app/nexus/internal/auth/oidc"
+	"github.com/spiffe/spike/app/nexus/internal/env"
+	"github.com/spiffe/spike/app/nexus/internal/state"
+	"github.com/spiffe/spike/internal/entity/v1/reqres"
+	"github.com/spiffe/spike/internal/log"
+	"github.com/spiffe/spike/internal/net"
+)
+
+// routeAdminLoginOIDC starts the authorization code + PKCE flow against
+// the OIDC provider configured via env.Oidc*, as an alternative to the
+// password path handled by routeAdminLogin. Password login remains
+// available side-by-side; this route only ever applies when the caller
+// selects it.
+//
+// It generates a fresh code_verifier/code_challenge pair and a state
+// nonce, stashes the verifier and an ID-token nonce in
+// state.SetPendingOIDCLogin keyed by that state value, and returns the
+// authorization URL the caller should redirect the admin's browser to.
+// routeAdminCallback completes the flow once the provider redirects back.
+//
+// Response Body on Success:
+//
+//	{
+//	  "authorization_url": "https://idp.example.com/authorize?..."
+//	}
+func routeAdminLoginOIDC(
+	w http.ResponseWriter, r *http.Request, audit *log.AuditEntry,
+) error {
+	log.Log().Info("routeAdminLoginOIDC", "method", r.Method, "path", r.URL.Path,
+		"query", r.URL.RawQuery)
+	audit.Action = "login-oidc"
+
+	issuer := env.OidcIssuer()
+	if issuer == "" {
+		log.Log().Info("routeAdminLoginOIDC", "msg", "OIDC login is not configured")
+
+		body := net.MarshalBody(reqres.AdminLoginOIDCResponse{
+			Err: reqres.ErrBadInput,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusNotFound, body, w)
+		return errors.New("oidc login is not configured")
+	}
+
+	discovery, err := oidc.Discover(issuer)
+	if err != nil {
+		log.Log().Error("routeAdminLoginOIDC", "msg", "Problem discovering OIDC provider",
+			"err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminLoginOIDCResponse{
+			Err: reqres.ErrServerFault,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, body, w)
+		return err
+	}
+
+	verifier, challenge, oidcState, err := oidc.NewPKCE()
+	if err != nil {
+		log.Log().Error("routeAdminLoginOIDC", "msg", "Problem generating PKCE parameters",
+			"err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminLoginOIDCResponse{
+			Err: reqres.ErrServerFault,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, body, w)
+		return err
+	}
+
+	nonce, err := oidc.NewNonce()
+	if err != nil {
+		log.Log().Error("routeAdminLoginOIDC", "msg", "Problem generating nonce",
+			"err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminLoginOIDCResponse{
+			Err: reqres.ErrServerFault,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, body, w)
+		return err
+	}
+
+	state.SetPendingOIDCLogin(oidcState, verifier, nonce)
+
+	authURL := oidc.AuthorizationURL(
+		discovery, env.OidcClientID(), env.OidcRedirectURL(), oidcState, challenge, nonce,
+	)
+
+	responseBody := net.MarshalBody(reqres.AdminLoginOIDCResponse{
+		AuthorizationURL: authURL,
+	}, w)
+	if responseBody == nil {
+		return errors.New("failed to marshal response body")
+	}
+
+	net.Respond(http.StatusOK, responseBody, w)
+	log.Log().Info("routeAdminLoginOIDC", "msg", "authorization url issued")
+	return nil
+}
@@ -0,0 +1,181 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/spiffe/spike/app/nexus/internal/auth/session"
+	"github.com/spiffe/spike/app/nexus/internal/env"
+	"github.com/spiffe/spike/app/nexus/internal/state"
+	"github.com/spiffe/spike/internal/entity/v1/reqres"
+	"github.com/spiffe/spike/internal/log"
+	"github.com/spiffe/spike/internal/net"
+)
+
+// issueSession registers a server-side session for the JWT routeAdminLogin
+// (or one of its OIDC/SVID equivalents) just minted, and sets it as a
+// Secure, HttpOnly, SameSite=Lax cookie so browser-based clients never
+// have to handle the token themselves. It returns the double-submit CSRF
+// token bound to the session in the X-CSRF-Token response header, which
+// the caller must echo back on any state-mutating request made over the
+// cookie.
+func issueSession(w http.ResponseWriter, token string) error {
+	jti, err := session.JTI(token)
+	if err != nil {
+		return err
+	}
+
+	csrfToken, err := session.NewCSRFToken()
+	if err != nil {
+		return err
+	}
+
+	ttl := env.SessionTTL()
+	state.RegisterSession(jti, csrfToken, ttl)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     env.SessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Domain:   env.SessionDomain(),
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.Header().Set(env.CSRFHeaderName, csrfToken)
+
+	return nil
+}
+
+// tokenFromRequest resolves the caller's JWT from either the
+// Authorization: Bearer header or the spike_session cookie, preferring
+// the header. The second return value reports whether the cookie was
+// the source, which RequireCSRF uses to decide whether the request needs
+// a CSRF token at all: a caller that sets Authorization explicitly
+// didn't get there via an ambient cookie a cross-site page could ride,
+// so double-submit has nothing to protect.
+func tokenFromRequest(r *http.Request) (token string, viaCookie bool) {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if t, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return t, false
+		}
+	}
+
+	cookie, err := r.Cookie(env.SessionCookieName)
+	if err != nil {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// RequireSession wraps any authenticated route so that a request
+// carrying a revoked or expired JWT is rejected before next runs,
+// regardless of whether the caller presented it via the Authorization
+// header or the session cookie. This is the check routeAdminLogout's
+// denylist exists to enforce: without it, a logged-out JWT would keep
+// authenticating every route that doesn't separately consult
+// state.ActiveSession.
+func RequireSession(
+	next func(http.ResponseWriter, *http.Request, *log.AuditEntry) error,
+) func(http.ResponseWriter, *http.Request, *log.AuditEntry) error {
+	return func(w http.ResponseWriter, r *http.Request, audit *log.AuditEntry) error {
+		token, _ := tokenFromRequest(r)
+		if token == "" {
+			net.Respond(http.StatusUnauthorized, nil, w)
+			return errors.New("missing credentials")
+		}
+
+		jti, err := session.JTI(token)
+		if err != nil {
+			net.Respond(http.StatusUnauthorized, nil, w)
+			return err
+		}
+
+		if _, ok := state.ActiveSession(jti); !ok {
+			net.Respond(http.StatusUnauthorized, nil, w)
+			return errors.New("session revoked or expired")
+		}
+
+		return next(w, r, audit)
+	}
+}
+
+// RequireCSRF wraps a state-mutating route (POST/PUT/DELETE) so that a
+// request authenticated via the session cookie must also present a
+// matching X-CSRF-Token header, the standard double-submit defense
+// against a cross-site request riding the admin's cookie. Requests
+// authenticated via an explicit Authorization header skip the
+// CSRF-header check, since a cross-site page cannot attach one, but
+// still go through RequireSession's revocation check like every other
+// route.
+func RequireCSRF(
+	next func(http.ResponseWriter, *http.Request, *log.AuditEntry) error,
+) func(http.ResponseWriter, *http.Request, *log.AuditEntry) error {
+	return RequireSession(func(w http.ResponseWriter, r *http.Request, audit *log.AuditEntry) error {
+		token, viaCookie := tokenFromRequest(r)
+		if !viaCookie {
+			return next(w, r, audit)
+		}
+
+		jti, err := session.JTI(token)
+		if err != nil {
+			net.Respond(http.StatusUnauthorized, nil, w)
+			return err
+		}
+
+		// RequireSession has already confirmed jti names an active
+		// session, so this lookup cannot fail.
+		csrfToken, _ := state.ActiveSession(jti)
+
+		if got := r.Header.Get(env.CSRFHeaderName); got == "" || got != csrfToken {
+			log.Log().Info("RequireCSRF", "msg", "missing or mismatched CSRF token")
+			net.Respond(http.StatusForbidden, nil, w)
+			return errors.New("missing or mismatched CSRF token")
+		}
+
+		return next(w, r, audit)
+	})
+}
+
+// routeAdminLogout clears the caller's session cookie and revokes the
+// underlying JWT via state.RevokeSession, so it can no longer pass
+// RequireCSRF even though it remains cryptographically valid until it
+// expires.
+func routeAdminLogout(
+	w http.ResponseWriter, r *http.Request, audit *log.AuditEntry,
+) error {
+	log.Log().Info("routeAdminLogout", "method", r.Method, "path", r.URL.Path)
+	audit.Action = "logout"
+
+	if token, _ := tokenFromRequest(r); token != "" {
+		if jti, err := session.JTI(token); err == nil {
+			state.RevokeSession(jti)
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     env.SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		Domain:   env.SessionDomain(),
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	responseBody := net.MarshalBody(reqres.AdminLogoutResponse{}, w)
+	if responseBody == nil {
+		return errors.New("failed to marshal response body")
+	}
+
+	net.Respond(http.StatusOK, responseBody, w)
+	log.Log().Info("routeAdminLogout", "msg", "session revoked")
+	return nil
+}
@@ -0,0 +1,157 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spiffe/spike/app/nexus/internal/env"
+	"github.com/spiffe/spike/app/nexus/internal/state"
+	"github.com/spiffe/spike/internal/log"
+)
+
+// fakeJWT builds an unsigned token carrying the given jti claim, enough
+// for tokenFromRequest/session.JTI to extract it - the same shape
+// auth/session's own tests use, since nothing on this path verifies the
+// signature.
+func fakeJWT(jti string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"jti":"` + jti + `"}`))
+	return header + "." + payload + ".sig"
+}
+
+func noopHandler() func(http.ResponseWriter, *http.Request, *log.AuditEntry) error {
+	return func(w http.ResponseWriter, r *http.Request, audit *log.AuditEntry) error {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+}
+
+func TestRequireSession_RejectsMissingCredentials(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	err := RequireSession(noopHandler())(w, r, &log.AuditEntry{})
+	if err == nil {
+		t.Fatalf("RequireSession accepted a request with no token")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireSession_RejectsRevokedTokenEvenViaBearerHeader(t *testing.T) {
+	token := fakeJWT("jti-revoked")
+	state.RegisterSession("jti-revoked", "csrf-token", time.Minute)
+	state.RevokeSession("jti-revoked")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	err := RequireSession(noopHandler())(w, r, &log.AuditEntry{})
+	if err == nil {
+		t.Fatalf("RequireSession accepted a revoked session presented via Authorization header")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireSession_AllowsActiveSessionViaBearerHeader(t *testing.T) {
+	token := fakeJWT("jti-active")
+	state.RegisterSession("jti-active", "csrf-token", time.Minute)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	if err := RequireSession(noopHandler())(w, r, &log.AuditEntry{}); err != nil {
+		t.Fatalf("RequireSession rejected an active session: %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestRequireCSRF_RejectsRevokedSessionEvenViaBearerHeader(t *testing.T) {
+	token := fakeJWT("jti-revoked-csrf")
+	state.RegisterSession("jti-revoked-csrf", "csrf-token", time.Minute)
+	state.RevokeSession("jti-revoked-csrf")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	err := RequireCSRF(noopHandler())(w, r, &log.AuditEntry{})
+	if err == nil {
+		t.Fatalf("RequireCSRF accepted a revoked session presented via Authorization header")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireCSRF_RejectsMissingCSRFTokenOverCookie(t *testing.T) {
+	token := fakeJWT("jti-cookie")
+	state.RegisterSession("jti-cookie", "csrf-token", time.Minute)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.AddCookie(&http.Cookie{Name: env.SessionCookieName, Value: token})
+
+	err := RequireCSRF(noopHandler())(w, r, &log.AuditEntry{})
+	if err == nil {
+		t.Fatalf("RequireCSRF accepted a cookie-borne request with no CSRF header")
+	}
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRequireCSRF_AllowsMatchingCSRFTokenOverCookie(t *testing.T) {
+	token := fakeJWT("jti-cookie-ok")
+	state.RegisterSession("jti-cookie-ok", "csrf-token", time.Minute)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.AddCookie(&http.Cookie{Name: env.SessionCookieName, Value: token})
+	r.Header.Set(env.CSRFHeaderName, "csrf-token")
+
+	if err := RequireCSRF(noopHandler())(w, r, &log.AuditEntry{}); err != nil {
+		t.Fatalf("RequireCSRF rejected a matching CSRF token: %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestRouteAdminLogout_RevokesSessionForSubsequentRequests(t *testing.T) {
+	token := fakeJWT("jti-logout")
+	state.RegisterSession("jti-logout", "csrf-token", time.Minute)
+
+	logoutReq := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	logoutReq.AddCookie(&http.Cookie{Name: env.SessionCookieName, Value: token})
+	logoutW := httptest.NewRecorder()
+
+	if err := routeAdminLogout(logoutW, logoutReq, &log.AuditEntry{}); err != nil {
+		t.Fatalf("routeAdminLogout returned error: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	if err := RequireSession(noopHandler())(w, r, &log.AuditEntry{}); err == nil {
+		t.Fatalf("RequireSession accepted a token routeAdminLogout had already revoked")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
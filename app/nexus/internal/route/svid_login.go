@@ -0,0 +1,115 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+
+	"github.com/spiffe/spike/app/nexus/internal/auth/svid"
+	"github.com/spiffe/spike/app/nexus/internal/env"
+	"github.com/spiffe/spike/app/nexus/internal/state"
+	"github.com/spiffe/spike/internal/entity/v1/reqres"
+	"github.com/spiffe/spike/internal/log"
+	"github.com/spiffe/spike/internal/net"
+)
+
+// source is the workload X.509 source Nexus uses to validate a caller's
+// presented SVID against the current trust bundle, the same source
+// poll.Tick uses to talk to Keepers.
+var source *workloadapi.X509Source
+
+// SetX509Source wires the workload X.509 source that routeAdminLoginSVID
+// uses to validate a caller's SPIFFE identity. It must be called once
+// during startup, with the same source passed to poll.Tick.
+func SetX509Source(s *workloadapi.X509Source) {
+	source = s
+}
+
+// routeAdminLoginSVID authenticates the caller by the X.509-SVID they
+// presented on the mTLS connection, rather than a password: it validates
+// the peer certificate chain against the trust bundle served by source,
+// extracts the caller's SPIFFE ID, and checks it against
+// env.AdminSPIFFEIDs. On success it mints the same JWT that
+// routeAdminLogin returns for password logins and registers it as the
+// same kind of cookie session via issueSession, letting a workload log
+// into Nexus without ever holding a shared secret.
+//
+// Response Body on Success:
+//
+//	{
+//	  "token": "signed_jwt_token"
+//	}
+func routeAdminLoginSVID(
+	w http.ResponseWriter, r *http.Request, audit *log.AuditEntry,
+) error {
+	log.Log().Info("routeAdminLoginSVID", "method", r.Method, "path", r.URL.Path,
+		"query", r.URL.RawQuery)
+	audit.Action = "login-svid"
+
+	caller, err := svid.AuthenticateRequest(r, source, env.AdminSPIFFEIDs())
+	if err != nil {
+		log.Log().Info("routeAdminLoginSVID", "msg", "unauthorized", "err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminLoginResponse{
+			Err: reqres.ErrUnauthorized,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusUnauthorized, body, w)
+		return err
+	}
+
+	log.Log().Info("routeAdminLoginSVID", "msg", "caller authenticated",
+		"spiffe_id", caller.SPIFFEID,
+		"subject", caller.Subject,
+		"fingerprint", caller.Sha256Fingerprint)
+
+	adminToken := state.AdminToken()
+	if adminToken == "" {
+		log.Log().Error("routeAdminLoginSVID", "msg", "Admin token not set")
+
+		body := net.MarshalBody(reqres.AdminLoginResponse{
+			Err: reqres.ErrServerFault,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, body, w)
+		return errors.New("admin token not set")
+	}
+
+	signedToken := net.CreateJwt(adminToken, w)
+	if signedToken == "" {
+		return errors.New("failed to sign token")
+	}
+
+	if err := issueSession(w, signedToken); err != nil {
+		log.Log().Error("routeAdminLoginSVID", "msg", "Problem issuing session", "err", err.Error())
+
+		body := net.MarshalBody(reqres.AdminLoginResponse{
+			Err: reqres.ErrServerFault,
+		}, w)
+		if body == nil {
+			return errors.New("failed to marshal response body")
+		}
+		net.Respond(http.StatusInternalServerError, body, w)
+		return err
+	}
+
+	responseBody := net.MarshalBody(reqres.AdminLoginResponse{
+		Token: signedToken,
+	}, w)
+	if responseBody == nil {
+		return errors.New("failed to marshal response body")
+	}
+
+	net.Respond(http.StatusOK, responseBody, w)
+	log.Log().Info("routeAdminLoginSVID", "msg", "authorized", "spiffe_id", caller.SPIFFEID)
+	return nil
+}
@@ -0,0 +1,58 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"sync"
+	"time"
+)
+
+// pendingOIDCLoginTTL bounds how long an in-flight authorization request
+// can sit between routeAdminLoginOIDC issuing it and routeAdminCallback
+// completing it, so an abandoned login can't be replayed indefinitely.
+const pendingOIDCLoginTTL = 10 * time.Minute
+
+// PendingOIDCLogin holds the PKCE verifier and nonce generated for a
+// single in-flight OIDC authorization request, keyed by its `state`
+// value, so routeAdminCallback can complete the flow it started.
+type PendingOIDCLogin struct {
+	CodeVerifier string
+	Nonce        string
+	expiresAt    time.Time
+}
+
+var (
+	pendingOIDCLoginsMu sync.Mutex
+	pendingOIDCLogins   = map[string]PendingOIDCLogin{}
+)
+
+// SetPendingOIDCLogin records a new in-flight OIDC login under its state
+// nonce.
+func SetPendingOIDCLogin(state, codeVerifier, nonce string) {
+	pendingOIDCLoginsMu.Lock()
+	defer pendingOIDCLoginsMu.Unlock()
+
+	pendingOIDCLogins[state] = PendingOIDCLogin{
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		expiresAt:    time.Now().Add(pendingOIDCLoginTTL),
+	}
+}
+
+// TakePendingOIDCLogin atomically looks up and removes the pending login
+// for state, so the same authorization code/state pair can never be
+// completed twice. The second return value is false if no pending login
+// exists for state, or if it has expired.
+func TakePendingOIDCLogin(state string) (PendingOIDCLogin, bool) {
+	pendingOIDCLoginsMu.Lock()
+	defer pendingOIDCLoginsMu.Unlock()
+
+	login, ok := pendingOIDCLogins[state]
+	delete(pendingOIDCLogins, state)
+	if !ok || time.Now().After(login.expiresAt) {
+		return PendingOIDCLogin{}, false
+	}
+	return login, true
+}
@@ -0,0 +1,66 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"sync"
+	"time"
+)
+
+// sessionRecord is a single signed-in admin session: the CSRF token
+// bound to it for the double-submit check, and when it stops being
+// valid.
+type sessionRecord struct {
+	csrfToken string
+	expiresAt time.Time
+}
+
+var (
+	sessionsMu sync.Mutex
+	sessions   = map[string]sessionRecord{}
+)
+
+// RegisterSession records a new session under jti, valid until ttl has
+// elapsed. routeAdminLogin and its OIDC/SVID equivalents call this right
+// after minting a JWT, so RequireCSRF and routeAdminLogout have
+// something to look the token up against.
+func RegisterSession(jti, csrfToken string, ttl time.Duration) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	sessions[jti] = sessionRecord{
+		csrfToken: csrfToken,
+		expiresAt: time.Now().Add(ttl),
+	}
+}
+
+// ActiveSession returns the CSRF token bound to jti and true, or ("",
+// false) if jti does not name a live, unexpired session - because it was
+// revoked by routeAdminLogout, it expired, or it was never registered
+// (e.g. a JWT minted before Nexus last restarted).
+func ActiveSession(jti string) (string, bool) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+
+	rec, ok := sessions[jti]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(rec.expiresAt) {
+		delete(sessions, jti)
+		return "", false
+	}
+	return rec.csrfToken, true
+}
+
+// RevokeSession removes jti's session. This is the server-side denylist
+// routeAdminLogout uses: the JWT itself stays cryptographically valid
+// until it expires, but ActiveSession now reports it as inactive, so
+// RequireCSRF rejects any state-mutating request that still carries it.
+func RevokeSession(jti string) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	delete(sessions, jti)
+}
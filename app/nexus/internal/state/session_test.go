@@ -0,0 +1,45 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActiveSession_UnknownJTIIsInactive(t *testing.T) {
+	if _, ok := ActiveSession("never-registered"); ok {
+		t.Fatalf("ActiveSession reported an unregistered jti as active")
+	}
+}
+
+func TestActiveSession_ReturnsBoundCSRFToken(t *testing.T) {
+	RegisterSession("jti-1", "csrf-1", time.Minute)
+
+	csrfToken, ok := ActiveSession("jti-1")
+	if !ok {
+		t.Fatalf("ActiveSession reported a freshly registered session as inactive")
+	}
+	if csrfToken != "csrf-1" {
+		t.Fatalf("ActiveSession csrf token = %q, want %q", csrfToken, "csrf-1")
+	}
+}
+
+func TestActiveSession_ExpiresAfterTTL(t *testing.T) {
+	RegisterSession("jti-2", "csrf-2", -time.Second)
+
+	if _, ok := ActiveSession("jti-2"); ok {
+		t.Fatalf("ActiveSession reported an expired session as active")
+	}
+}
+
+func TestRevokeSession_LogoutInvalidatesSession(t *testing.T) {
+	RegisterSession("jti-3", "csrf-3", time.Minute)
+	RevokeSession("jti-3")
+
+	if _, ok := ActiveSession("jti-3"); ok {
+		t.Fatalf("ActiveSession reported a revoked session as active")
+	}
+}
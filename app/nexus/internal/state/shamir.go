@@ -0,0 +1,73 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/spiffe/spike/internal/log"
+	"github.com/spiffe/spike/internal/store"
+)
+
+var (
+	rootKeyVersionMu sync.RWMutex
+	rootKeyVersion   uint32
+)
+
+// RootKeyVersion returns the current root key generation. It is
+// incremented every time the root key is rotated, so that Shamir shares
+// minted for one generation can never be mixed with another's during
+// recovery.
+func RootKeyVersion() uint32 {
+	rootKeyVersionMu.RLock()
+	defer rootKeyVersionMu.RUnlock()
+	return rootKeyVersion
+}
+
+// BumpRootKeyVersion increments and returns the root key generation. Call
+// this whenever the root key is rotated, before re-splitting it.
+func BumpRootKeyVersion() uint32 {
+	rootKeyVersionMu.Lock()
+	defer rootKeyVersionMu.Unlock()
+	rootKeyVersion++
+	return rootKeyVersion
+}
+
+func shamirMACKeyRecord(version uint32) string {
+	return fmt.Sprintf("shamir-mac-key/%d", version)
+}
+
+// SetShamirMACKey persists the MAC key generated the last time the root
+// key was split into Shamir shares, keyed by version, so poll.Recover can
+// still authenticate shares fetched from Keepers after Nexus restarts -
+// the exact scenario it exists for. Unlike AdminToken and
+// AdminCredentials, it is stored unencrypted, for the same reason
+// RootKey is: Recover runs precisely when RootKey is empty, so there is
+// nothing yet to derive an encryption key from.
+func SetShamirMACKey(version uint32, macKey []byte) {
+	if _, err := currentBackend().Put(
+		context.Background(), shamirMACKeyRecord(version), macKey,
+	); err != nil {
+		log.Log().Error("state.SetShamirMACKey", "msg", "Problem persisting MAC key", "err", err.Error())
+	}
+}
+
+// ShamirMACKey returns the MAC key recorded for the given root key
+// version, and whether one was found. A false result means Nexus never
+// split a key under that version, in which case recovered shares cannot
+// be authenticated and must be rejected.
+func ShamirMACKey(version uint32) ([]byte, bool) {
+	rec, err := currentBackend().Get(context.Background(), shamirMACKeyRecord(version))
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Log().Error("state.ShamirMACKey", "msg", "Problem reading MAC key", "err", err.Error())
+		}
+		return nil, false
+	}
+	return rec.Value, true
+}
@@ -0,0 +1,198 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package state
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/spiffe/spike/internal/log"
+	"github.com/spiffe/spike/internal/store"
+)
+
+const (
+	rootKeyRecord          = "root-key"
+	adminTokenRecord       = "admin-token"
+	adminCredentialsRecord = "admin-credentials"
+	stateEncryptionKeyInfo = "spike-nexus-state-encryption"
+)
+
+var (
+	backendMu sync.RWMutex
+	backend   store.Store = store.NewMemoryStore()
+)
+
+// UseStore replaces the backend Nexus persists the root key, admin
+// token, and admin credentials to. It must be called once during startup
+// before any of those values are read or written; the default is an
+// in-memory store, matching the process-lifetime-only behavior Nexus had
+// before a pluggable backend existed.
+func UseStore(s store.Store) {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backend = s
+}
+
+// Credentials is the stored admin credential: a password hash (Argon2id
+// PHC-encoded, or a legacy bare-hex PBKDF2-SHA256 hash) and, for the
+// legacy format only, the salt it was computed with.
+type Credentials struct {
+	PasswordHash string
+	Salt         string
+}
+
+// RootKey returns the current root key, or "" if none has been set yet
+// in this process. Unlike AdminToken and AdminCredentials, it is stored
+// unencrypted: it is the key everything else in this file is encrypted
+// under, so there is nothing left to encrypt it with. With a persistent
+// driver the whole key is written to that backend as is; poll.Tick's
+// Shamir shares are sent to Keepers in addition, not instead.
+func RootKey() string {
+	ctx := context.Background()
+	rec, err := currentBackend().Get(ctx, rootKeyRecord)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Log().Error("state.RootKey", "msg", "Problem reading root key", "err", err.Error())
+		}
+		return ""
+	}
+	return string(rec.Value)
+}
+
+// SetRootKey persists the root key.
+func SetRootKey(key string) {
+	if _, err := currentBackend().Put(context.Background(), rootKeyRecord, []byte(key)); err != nil {
+		log.Log().Error("state.SetRootKey", "msg", "Problem persisting root key", "err", err.Error())
+	}
+}
+
+// AdminToken returns the current admin token, or "" if none has been set
+// yet in this process.
+func AdminToken() string {
+	value, ok := getEncrypted(adminTokenRecord)
+	if !ok {
+		return ""
+	}
+	return string(value)
+}
+
+// SetAdminToken persists token, encrypted under a key derived from the
+// current root key. Callers set the root key before calling this, as
+// updateStateForInit does.
+func SetAdminToken(token string) {
+	putEncrypted(adminTokenRecord, []byte(token))
+}
+
+// AdminCredentials returns the current admin credential, or the zero
+// value if none has been set yet in this process.
+func AdminCredentials() Credentials {
+	value, ok := getEncrypted(adminCredentialsRecord)
+	if !ok {
+		return Credentials{}
+	}
+
+	hash, salt, ok := splitCredentialRecord(value)
+	if !ok {
+		log.Log().Error("state.AdminCredentials", "msg", "malformed stored credential")
+		return Credentials{}
+	}
+	return Credentials{PasswordHash: hash, Salt: salt}
+}
+
+// SetAdminCredentials persists passwordHash and salt as the admin
+// credential, encrypted under a key derived from the current root key.
+// salt is only meaningful for the legacy PBKDF2 format; password.Hash's
+// Argon2id output embeds its own salt and passes "" here.
+func SetAdminCredentials(passwordHash, salt string) {
+	putEncrypted(adminCredentialsRecord, joinCredentialRecord(passwordHash, salt))
+}
+
+func currentBackend() store.Store {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend
+}
+
+// stateEncryptionKey derives a 32-byte AES-256 key from the current root
+// key via HKDF-SHA256, so admin token and admin credential records can
+// only be decrypted by a Nexus that currently holds the root key. The
+// second return value is false if no root key has been set yet.
+func stateEncryptionKey() ([]byte, bool) {
+	rootKey := RootKey()
+	if rootKey == "" {
+		return nil, false
+	}
+
+	key := make([]byte, 32)
+	kdf := hkdf.New(sha256.New, []byte(rootKey), nil, []byte(stateEncryptionKeyInfo))
+	if _, err := io.ReadFull(kdf, key); err != nil {
+		log.Log().Error("state.stateEncryptionKey", "msg", "Problem deriving state encryption key", "err", err.Error())
+		return nil, false
+	}
+	return key, true
+}
+
+func getEncrypted(key string) ([]byte, bool) {
+	encKey, ok := stateEncryptionKey()
+	if !ok {
+		return nil, false
+	}
+
+	enc, err := store.NewEncryptedStore(currentBackend(), encKey)
+	if err != nil {
+		log.Log().Error("state.getEncrypted", "msg", "Problem wrapping store", "err", err.Error())
+		return nil, false
+	}
+
+	rec, err := enc.Get(context.Background(), key)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Log().Error("state.getEncrypted", "msg", "Problem reading record", "key", key, "err", err.Error())
+		}
+		return nil, false
+	}
+	return rec.Value, true
+}
+
+func putEncrypted(key string, value []byte) {
+	encKey, ok := stateEncryptionKey()
+	if !ok {
+		log.Log().Error("state.putEncrypted", "msg", "root key not set; refusing to persist record", "key", key)
+		return
+	}
+
+	enc, err := store.NewEncryptedStore(currentBackend(), encKey)
+	if err != nil {
+		log.Log().Error("state.putEncrypted", "msg", "Problem wrapping store", "err", err.Error())
+		return
+	}
+
+	if _, err := enc.Put(context.Background(), key, value); err != nil {
+		log.Log().Error("state.putEncrypted", "msg", "Problem persisting record", "key", key, "err", err.Error())
+	}
+}
+
+// credentialSep separates the password hash from the legacy salt in a
+// stored credential record. Argon2id PHC strings and legacy hex hashes
+// never contain it, so a single byte is enough.
+const credentialSep = '\x00'
+
+func joinCredentialRecord(passwordHash, salt string) []byte {
+	return append(append([]byte(passwordHash), credentialSep), []byte(salt)...)
+}
+
+func splitCredentialRecord(raw []byte) (hash, salt string, ok bool) {
+	for i, b := range raw {
+		if b == credentialSep {
+			return string(raw[:i]), string(raw[i+1:]), true
+		}
+	}
+	return "", "", false
+}
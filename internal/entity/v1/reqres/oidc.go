@@ -0,0 +1,28 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package reqres
+
+// AdminLoginOIDCResponse carries the URL the admin's browser should be
+// redirected to in order to start the authorization code + PKCE flow.
+type AdminLoginOIDCResponse struct {
+	AuthorizationURL string    `json:"authorization_url,omitempty"`
+	Err              ErrorCode `json:"err,omitempty"`
+}
+
+// AdminCallbackRequest is the JSON body routeAdminCallback reads: the code
+// and state the OIDC provider returned when it redirected the browser back
+// after the admin authenticated.
+type AdminCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+// AdminCallbackResponse mirrors AdminLoginResponse: a signed JWT on
+// success, so the OIDC path issues the exact same token shape as the
+// password path.
+type AdminCallbackResponse struct {
+	Token string    `json:"token,omitempty"`
+	Err   ErrorCode `json:"err,omitempty"`
+}
@@ -0,0 +1,12 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package reqres
+
+// AdminLogoutResponse is returned by routeAdminLogout. A successful
+// logout carries no data beyond the 200 status and the cleared session
+// cookie.
+type AdminLogoutResponse struct {
+	Err ErrorCode `json:"err,omitempty"`
+}
@@ -0,0 +1,39 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package reqres
+
+// ShardUpdateRequest is sent by Nexus to push a single Shamir share of the
+// root key to a Keeper, replacing the full-key payload that
+// net.UpdateCache used to send.
+type ShardUpdateRequest struct {
+	// Version identifies the key generation this share belongs to.
+	Version uint32 `json:"version"`
+	// Index is the Shamir polynomial index this Keeper's share was
+	// evaluated at.
+	Index byte `json:"index"`
+	// Share is the hex-encoded share payload for this Keeper.
+	Share string `json:"share"`
+	// MAC is the hex-encoded HMAC-SHA256 tag over the share, which
+	// Nexus uses to detect a corrupted share during recovery.
+	MAC string `json:"mac"`
+}
+
+// ShardUpdateResponse acknowledges a ShardUpdateRequest.
+type ShardUpdateResponse struct {
+	Err ErrorCode `json:"err,omitempty"`
+}
+
+// ShardShowRequest is sent by Nexus to a Keeper during recovery to ask for
+// that Keeper's share of the current root key.
+type ShardShowRequest struct{}
+
+// ShardShowResponse returns a single Keeper's Shamir share.
+type ShardShowResponse struct {
+	Version uint32    `json:"version"`
+	Index   byte      `json:"index"`
+	Share   string    `json:"share"`
+	MAC     string    `json:"mac"`
+	Err     ErrorCode `json:"err,omitempty"`
+}
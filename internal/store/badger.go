@@ -0,0 +1,162 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+//go:build !nobadger
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+func init() {
+	Register("badger", func(dsn string) (Store, error) {
+		return newBadgerStore(dsn)
+	})
+}
+
+// badgerStore persists records in a Badger key-value database. Like
+// boltStore, the CAS version is encoded alongside the value rather than
+// in a separate key, since Badger has no native optimistic-concurrency
+// primitive outside of its transaction conflict detection.
+type badgerStore struct {
+	db *badger.DB
+}
+
+func newBadgerStore(dir string) (*badgerStore, error) {
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		return nil, fmt.Errorf("store/badger: opening %q: %w", dir, err)
+	}
+	return &badgerStore{db: db}, nil
+}
+
+func (s *badgerStore) Get(_ context.Context, key string) (Record, error) {
+	var rec Record
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err == badger.ErrKeyNotFound {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return item.Value(func(raw []byte) error {
+			var decodeErr error
+			rec, decodeErr = decodeBadgerRecord(raw)
+			return decodeErr
+		})
+	})
+	return rec, err
+}
+
+func (s *badgerStore) Put(_ context.Context, key string, value []byte) (string, error) {
+	var cas string
+	err := s.db.Update(func(txn *badger.Txn) error {
+		version := nextBadgerVersion(txn, key)
+		cas = strconv.FormatUint(version, 10)
+		return txn.Set([]byte(key), encodeBadgerRecord(cas, value))
+	})
+	return cas, err
+}
+
+func (s *badgerStore) Delete(_ context.Context, key string) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+}
+
+func (s *badgerStore) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(prefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek([]byte(prefix)); it.ValidForPrefix([]byte(prefix)); it.Next() {
+			keys = append(keys, string(it.Item().Key()))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (s *badgerStore) AtomicUpdate(_ context.Context, key, expectedCAS string, newValue []byte) (string, error) {
+	var cas string
+	err := s.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		exists := err == nil
+		if err != nil && err != badger.ErrKeyNotFound {
+			return err
+		}
+
+		switch {
+		case expectedCAS == "" && exists:
+			return ErrCASMismatch
+		case expectedCAS != "":
+			if !exists {
+				return ErrCASMismatch
+			}
+			var current Record
+			if err := item.Value(func(raw []byte) error {
+				var decodeErr error
+				current, decodeErr = decodeBadgerRecord(raw)
+				return decodeErr
+			}); err != nil || current.CAS != expectedCAS {
+				return ErrCASMismatch
+			}
+		}
+
+		version := nextBadgerVersion(txn, key)
+		cas = strconv.FormatUint(version, 10)
+		return txn.Set([]byte(key), encodeBadgerRecord(cas, newValue))
+	})
+	return cas, err
+}
+
+func (s *badgerStore) Close() error {
+	return s.db.Close()
+}
+
+func nextBadgerVersion(txn *badger.Txn, key string) uint64 {
+	item, err := txn.Get([]byte(key))
+	if err != nil {
+		return 1
+	}
+	var rec Record
+	if err := item.Value(func(raw []byte) error {
+		var decodeErr error
+		rec, decodeErr = decodeBadgerRecord(raw)
+		return decodeErr
+	}); err != nil {
+		return 1
+	}
+	v, err := strconv.ParseUint(rec.CAS, 10, 64)
+	if err != nil {
+		return 1
+	}
+	return v + 1
+}
+
+// encodeBadgerRecord and decodeBadgerRecord encode a CAS version
+// alongside the value, the same scheme boltStore uses, kept as its own
+// copy rather than a shared helper so a badger-only build (e.g.
+// -tags=nobbolt,nosqlite,nopostgres) never needs bbolt.go in the build.
+func encodeBadgerRecord(cas string, value []byte) []byte {
+	return append([]byte(cas+"\n"), value...)
+}
+
+func decodeBadgerRecord(raw []byte) (Record, error) {
+	idx := strings.IndexByte(string(raw), '\n')
+	if idx < 0 {
+		return Record{}, fmt.Errorf("store/badger: malformed record")
+	}
+	return Record{CAS: string(raw[:idx]), Value: raw[idx+1:]}, nil
+}
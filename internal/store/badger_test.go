@@ -0,0 +1,18 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+//go:build !nobadger
+
+package store
+
+import "testing"
+
+func TestBadgerStore_Conformance(t *testing.T) {
+	s, err := newBadgerStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("newBadgerStore: %v", err)
+	}
+	defer s.Close()
+	conformance(t, s)
+}
@@ -0,0 +1,149 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+//go:build !nobbolt
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func init() {
+	Register("bbolt", func(dsn string) (Store, error) {
+		return newBoltStore(dsn)
+	})
+}
+
+var boltBucket = []byte("spike")
+
+// boltStore persists records in a single bbolt bucket, encoding each
+// value as `<cas version>\n<raw bytes>` so Get/AtomicUpdate can recover
+// the CAS token without a second bucket.
+type boltStore struct {
+	db *bolt.DB
+}
+
+func newBoltStore(path string) (*boltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("store/bbolt: opening %q: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("store/bbolt: creating bucket: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func (b *boltStore) Get(_ context.Context, key string) (Record, error) {
+	var rec Record
+	err := b.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(boltBucket).Get([]byte(key))
+		if raw == nil {
+			return ErrNotFound
+		}
+		var err error
+		rec, err = decodeBoltRecord(raw)
+		return err
+	})
+	return rec, err
+}
+
+func (b *boltStore) Put(_ context.Context, key string, value []byte) (string, error) {
+	var cas string
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		version := nextBoltVersion(bucket, key)
+		cas = strconv.FormatUint(version, 10)
+		return bucket.Put([]byte(key), encodeBoltRecord(cas, value))
+	})
+	return cas, err
+}
+
+func (b *boltStore) Delete(_ context.Context, key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+}
+
+func (b *boltStore) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+		p := []byte(prefix)
+		for k, _ := c.Seek(p); k != nil && strings.HasPrefix(string(k), prefix); k, _ = c.Next() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (b *boltStore) AtomicUpdate(_ context.Context, key, expectedCAS string, newValue []byte) (string, error) {
+	var cas string
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		raw := bucket.Get([]byte(key))
+
+		switch {
+		case expectedCAS == "" && raw != nil:
+			return ErrCASMismatch
+		case expectedCAS != "":
+			current, err := decodeBoltRecord(raw)
+			if raw == nil || err != nil || current.CAS != expectedCAS {
+				return ErrCASMismatch
+			}
+		}
+
+		version := nextBoltVersion(bucket, key)
+		cas = strconv.FormatUint(version, 10)
+		return bucket.Put([]byte(key), encodeBoltRecord(cas, newValue))
+	})
+	return cas, err
+}
+
+func (b *boltStore) Close() error {
+	return b.db.Close()
+}
+
+func nextBoltVersion(bucket *bolt.Bucket, key string) uint64 {
+	raw := bucket.Get([]byte(key))
+	if raw == nil {
+		return 1
+	}
+	rec, err := decodeBoltRecord(raw)
+	if err != nil {
+		return 1
+	}
+	v, err := strconv.ParseUint(rec.CAS, 10, 64)
+	if err != nil {
+		return 1
+	}
+	return v + 1
+}
+
+func encodeBoltRecord(cas string, value []byte) []byte {
+	return append([]byte(cas+"\n"), value...)
+}
+
+func decodeBoltRecord(raw []byte) (Record, error) {
+	idx := strings.IndexByte(string(raw), '\n')
+	if idx < 0 {
+		return Record{}, fmt.Errorf("store/bbolt: malformed record")
+	}
+	return Record{CAS: string(raw[:idx]), Value: raw[idx+1:]}, nil
+}
@@ -0,0 +1,21 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+//go:build !nobbolt
+
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBoltStore_Conformance(t *testing.T) {
+	s, err := newBoltStore(filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatalf("newBoltStore: %v", err)
+	}
+	defer s.Close()
+	conformance(t, s)
+}
@@ -0,0 +1,100 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// EncryptedStore wraps another Store and transparently encrypts every
+// value with AES-GCM before it reaches the underlying driver, using a
+// fresh random nonce per record. Keys and CAS tokens pass through
+// unmodified: a driver still needs the key to address a record and the
+// CAS token to implement AtomicUpdate, and neither leaks the value.
+type EncryptedStore struct {
+	inner Store
+	aead  cipher.AEAD
+}
+
+// NewEncryptedStore wraps inner so every value it stores is encrypted
+// under key, which must be 16, 24, or 32 bytes (AES-128/192/256).
+// Callers typically derive key from the root key so records can only be
+// read back by a Nexus that currently holds it.
+func NewEncryptedStore(inner Store, key []byte) (*EncryptedStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("store: creating AES cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("store: creating AES-GCM: %w", err)
+	}
+	return &EncryptedStore{inner: inner, aead: aead}, nil
+}
+
+func (e *EncryptedStore) seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("store: generating nonce: %w", err)
+	}
+	return e.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (e *EncryptedStore) open(ciphertext []byte) ([]byte, error) {
+	nonceSize := e.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("store: ciphertext shorter than nonce")
+	}
+	nonce, sealed := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := e.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("store: decrypting record: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (e *EncryptedStore) Get(ctx context.Context, key string) (Record, error) {
+	rec, err := e.inner.Get(ctx, key)
+	if err != nil {
+		return Record{}, err
+	}
+	plaintext, err := e.open(rec.Value)
+	if err != nil {
+		return Record{}, err
+	}
+	return Record{Value: plaintext, CAS: rec.CAS}, nil
+}
+
+func (e *EncryptedStore) Put(ctx context.Context, key string, value []byte) (string, error) {
+	sealed, err := e.seal(value)
+	if err != nil {
+		return "", err
+	}
+	return e.inner.Put(ctx, key, sealed)
+}
+
+func (e *EncryptedStore) Delete(ctx context.Context, key string) error {
+	return e.inner.Delete(ctx, key)
+}
+
+func (e *EncryptedStore) List(ctx context.Context, prefix string) ([]string, error) {
+	return e.inner.List(ctx, prefix)
+}
+
+func (e *EncryptedStore) AtomicUpdate(ctx context.Context, key, expectedCAS string, newValue []byte) (string, error) {
+	sealed, err := e.seal(newValue)
+	if err != nil {
+		return "", err
+	}
+	return e.inner.AtomicUpdate(ctx, key, expectedCAS, sealed)
+}
+
+func (e *EncryptedStore) Close() error {
+	return e.inner.Close()
+}
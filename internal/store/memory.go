@@ -0,0 +1,95 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is the default driver: an in-memory map, matching the
+// process-lifetime-only behavior SPIKE Nexus and SPIKE Keeper had before
+// a pluggable backend existed. It is always compiled in, regardless of
+// which other drivers a build tags in or out, so there is always at
+// least one working driver.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string]Record
+	version map[string]uint64
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]Record),
+		version: make(map[string]uint64),
+	}
+}
+
+func (m *MemoryStore) Get(_ context.Context, key string) (Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.records[key]
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+	return r, nil
+}
+
+func (m *MemoryStore) Put(_ context.Context, key string, value []byte) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.putLocked(key, value), nil
+}
+
+func (m *MemoryStore) putLocked(key string, value []byte) string {
+	m.version[key]++
+	cas := strconv.FormatUint(m.version[key], 10)
+	m.records[key] = Record{Value: value, CAS: cas}
+	return cas
+}
+
+func (m *MemoryStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, key)
+	delete(m.version, key)
+	return nil
+}
+
+func (m *MemoryStore) List(_ context.Context, prefix string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var keys []string
+	for k := range m.records {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (m *MemoryStore) AtomicUpdate(_ context.Context, key, expectedCAS string, newValue []byte) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, exists := m.records[key]
+	switch {
+	case expectedCAS == "" && exists:
+		return "", ErrCASMismatch
+	case expectedCAS != "" && (!exists || current.CAS != expectedCAS):
+		return "", ErrCASMismatch
+	}
+
+	return m.putLocked(key, newValue), nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,33 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import "context"
+
+// Migrate copies every record under prefix from src to dst, driver to
+// driver, by reading each value out of src and writing it into dst with
+// Put. It does not delete anything from src: an operator re-runs the
+// binary that calls this (see app/migrate) pointed at the old and new
+// DSNs, confirms the new driver looks right, and only then switches Nexus
+// and Keeper over. It returns the number of records copied.
+func Migrate(ctx context.Context, src, dst Store, prefix string) (int, error) {
+	keys, err := src.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	for _, key := range keys {
+		rec, err := src.Get(ctx, key)
+		if err != nil {
+			return copied, err
+		}
+		if _, err := dst.Put(ctx, key, rec.Value); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+	return copied, nil
+}
@@ -0,0 +1,193 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+//go:build !nopostgres
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func init() {
+	Register("postgres", func(dsn string) (Store, error) {
+		return newPostgresStore(dsn)
+	})
+}
+
+// postgresStore persists records in a single "spike_records" table,
+// guarded by the implicit row lock `SELECT ... FOR UPDATE` takes, which
+// is what gives AtomicUpdate its compare-and-swap semantics across
+// concurrent Nexus replicas talking to the same database.
+type postgresStore struct {
+	db *sql.DB
+}
+
+func newPostgresStore(dsn string) (*postgresStore, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("store/postgres: opening: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("store/postgres: connecting: %w", err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS spike_records (
+	key     TEXT PRIMARY KEY,
+	version BIGINT NOT NULL,
+	value   BYTEA NOT NULL
+)`
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("store/postgres: creating schema: %w", err)
+	}
+
+	return &postgresStore{db: db}, nil
+}
+
+func (p *postgresStore) Get(ctx context.Context, key string) (Record, error) {
+	var version int64
+	var value []byte
+	row := p.db.QueryRowContext(ctx,
+		`SELECT version, value FROM spike_records WHERE key = $1`, key)
+	if err := row.Scan(&version, &value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Record{}, ErrNotFound
+		}
+		return Record{}, fmt.Errorf("store/postgres: get %q: %w", key, err)
+	}
+	return Record{Value: value, CAS: strconv.FormatInt(version, 10)}, nil
+}
+
+func (p *postgresStore) Put(ctx context.Context, key string, value []byte) (string, error) {
+	var cas string
+	err := withPostgresTx(ctx, p.db, func(tx *sql.Tx) error {
+		version, err := nextPostgresVersion(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		cas = strconv.FormatInt(version, 10)
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO spike_records (key, version, value) VALUES ($1, $2, $3)
+ON CONFLICT (key) DO UPDATE SET version = excluded.version, value = excluded.value`,
+			key, version, value)
+		return err
+	})
+	return cas, err
+}
+
+func (p *postgresStore) Delete(ctx context.Context, key string) error {
+	_, err := p.db.ExecContext(ctx, `DELETE FROM spike_records WHERE key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("store/postgres: delete %q: %w", key, err)
+	}
+	return nil
+}
+
+func (p *postgresStore) List(ctx context.Context, prefix string) ([]string, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT key FROM spike_records WHERE key LIKE $1`, prefix+"%")
+	if err != nil {
+		return nil, fmt.Errorf("store/postgres: list %q: %w", prefix, err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, fmt.Errorf("store/postgres: scanning key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (p *postgresStore) AtomicUpdate(ctx context.Context, key, expectedCAS string, newValue []byte) (string, error) {
+	var cas string
+	err := withPostgresTx(ctx, p.db, func(tx *sql.Tx) error {
+		var current sql.NullInt64
+		row := tx.QueryRowContext(ctx,
+			`SELECT version FROM spike_records WHERE key = $1 FOR UPDATE`, key)
+		switch err := row.Scan(&current); {
+		case errors.Is(err, sql.ErrNoRows):
+			// leave current invalid: key does not exist.
+		case err != nil:
+			return fmt.Errorf("store/postgres: reading current version: %w", err)
+		}
+
+		exists := current.Valid
+		switch {
+		case expectedCAS == "" && exists:
+			return ErrCASMismatch
+		case expectedCAS != "" && (!exists || strconv.FormatInt(current.Int64, 10) != expectedCAS):
+			return ErrCASMismatch
+		}
+
+		version := int64(1)
+		if exists {
+			version = current.Int64 + 1
+		}
+		cas = strconv.FormatInt(version, 10)
+		if !exists {
+			// No row to lock yet: a concurrent creator's insert wins and
+			// this one reports a CAS mismatch instead of overwriting it.
+			res, err := tx.ExecContext(ctx, `
+INSERT INTO spike_records (key, version, value) VALUES ($1, $2, $3)
+ON CONFLICT (key) DO NOTHING`,
+				key, version, newValue)
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil || n == 0 {
+				return ErrCASMismatch
+			}
+			return nil
+		}
+		_, err := tx.ExecContext(ctx, `
+INSERT INTO spike_records (key, version, value) VALUES ($1, $2, $3)
+ON CONFLICT (key) DO UPDATE SET version = excluded.version, value = excluded.value`,
+			key, version, newValue)
+		return err
+	})
+	return cas, err
+}
+
+func (p *postgresStore) Close() error {
+	return p.db.Close()
+}
+
+func withPostgresTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("store/postgres: starting transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func nextPostgresVersion(ctx context.Context, tx *sql.Tx, key string) (int64, error) {
+	var version int64
+	row := tx.QueryRowContext(ctx,
+		`SELECT version FROM spike_records WHERE key = $1 FOR UPDATE`, key)
+	switch err := row.Scan(&version); {
+	case errors.Is(err, sql.ErrNoRows):
+		return 1, nil
+	case err != nil:
+		return 0, fmt.Errorf("store/postgres: reading current version: %w", err)
+	}
+	return version + 1, nil
+}
@@ -0,0 +1,29 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+//go:build !nopostgres
+
+package store
+
+import (
+	"os"
+	"testing"
+)
+
+// TestPostgresStore_Conformance only runs when SPIKE_TEST_POSTGRES_DSN
+// points at a reachable database: there is no in-process Postgres to
+// spin up the way there is for the embedded drivers.
+func TestPostgresStore_Conformance(t *testing.T) {
+	dsn := os.Getenv("SPIKE_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("SPIKE_TEST_POSTGRES_DSN not set; skipping Postgres conformance test")
+	}
+
+	s, err := newPostgresStore(dsn)
+	if err != nil {
+		t.Fatalf("newPostgresStore: %v", err)
+	}
+	defer s.Close()
+	conformance(t, s)
+}
@@ -0,0 +1,51 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import "fmt"
+
+// Factory builds a Store driver from a driver-specific connection
+// string (a file path for bbolt/sqlite, a DSN for postgres, a data
+// directory for badger; ignored by memory).
+type Factory func(dsn string) (Store, error)
+
+// drivers holds every driver compiled into this binary. Each driver
+// file's init() registers itself here, guarded by its own build tag, so
+// the set of supported names varies with which tags were passed to `go
+// build`.
+var drivers = map[string]Factory{
+	"memory": func(string) (Store, error) { return NewMemoryStore(), nil },
+}
+
+// Register adds a driver under name. Driver files call this from
+// init(); it panics on a duplicate name since that can only indicate two
+// drivers compiled in under the same name, a build-time mistake.
+func Register(name string, factory Factory) {
+	if _, exists := drivers[name]; exists {
+		panic(fmt.Sprintf("store: driver %q registered twice", name))
+	}
+	drivers[name] = factory
+}
+
+// New builds the named driver with the given connection string. It
+// returns an error if name was not registered, either because it is
+// misspelled or because the binary was built with that driver's tag
+// disabling it (e.g. -tags=nobbolt).
+func New(name, dsn string) (Store, error) {
+	factory, ok := drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("store: unknown or disabled driver %q", name)
+	}
+	return factory(dsn)
+}
+
+// Available returns the names of every driver compiled into this binary.
+func Available() []string {
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	return names
+}
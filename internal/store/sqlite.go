@@ -0,0 +1,187 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+//go:build !nosqlite
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+func init() {
+	Register("sqlite", func(dsn string) (Store, error) {
+		return newSQLiteStore(dsn)
+	})
+}
+
+// sqliteStore persists records in a single "records" table, with the CAS
+// token kept as its own "version" column rather than encoded alongside
+// the value, since SQL gives us a real column to put it in.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func newSQLiteStore(dsn string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("store/sqlite: opening %q: %w", dsn, err)
+	}
+
+	// A single file-backed connection handles writes serially; sqlite
+	// rejects concurrent writers from multiple connections with
+	// SQLITE_BUSY, which the CAS semantics below don't retry around.
+	db.SetMaxOpenConns(1)
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS records (
+	key     TEXT PRIMARY KEY,
+	version INTEGER NOT NULL,
+	value   BLOB NOT NULL
+)`
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("store/sqlite: creating schema: %w", err)
+	}
+
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) Get(ctx context.Context, key string) (Record, error) {
+	var version int64
+	var value []byte
+	row := s.db.QueryRowContext(ctx,
+		`SELECT version, value FROM records WHERE key = ?`, key)
+	if err := row.Scan(&version, &value); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Record{}, ErrNotFound
+		}
+		return Record{}, fmt.Errorf("store/sqlite: get %q: %w", key, err)
+	}
+	return Record{Value: value, CAS: strconv.FormatInt(version, 10)}, nil
+}
+
+func (s *sqliteStore) Put(ctx context.Context, key string, value []byte) (string, error) {
+	var cas string
+	err := withSQLiteTx(ctx, s.db, func(tx *sql.Tx) error {
+		version, err := nextSQLiteVersion(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		cas = strconv.FormatInt(version, 10)
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO records (key, version, value) VALUES (?, ?, ?)
+ON CONFLICT(key) DO UPDATE SET version = excluded.version, value = excluded.value`,
+			key, version, value)
+		return err
+	})
+	return cas, err
+}
+
+func (s *sqliteStore) Delete(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM records WHERE key = ?`, key)
+	if err != nil {
+		return fmt.Errorf("store/sqlite: delete %q: %w", key, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) List(ctx context.Context, prefix string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT key FROM records WHERE key LIKE ? ESCAPE '\'`, escapeSQLiteLike(prefix)+"%")
+	if err != nil {
+		return nil, fmt.Errorf("store/sqlite: list %q: %w", prefix, err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, fmt.Errorf("store/sqlite: scanning key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (s *sqliteStore) AtomicUpdate(ctx context.Context, key, expectedCAS string, newValue []byte) (string, error) {
+	var cas string
+	err := withSQLiteTx(ctx, s.db, func(tx *sql.Tx) error {
+		var current sql.NullInt64
+		row := tx.QueryRowContext(ctx, `SELECT version FROM records WHERE key = ?`, key)
+		switch err := row.Scan(&current); {
+		case errors.Is(err, sql.ErrNoRows):
+			// leave current invalid: key does not exist.
+		case err != nil:
+			return fmt.Errorf("store/sqlite: reading current version: %w", err)
+		}
+
+		exists := current.Valid
+		switch {
+		case expectedCAS == "" && exists:
+			return ErrCASMismatch
+		case expectedCAS != "" && (!exists || strconv.FormatInt(current.Int64, 10) != expectedCAS):
+			return ErrCASMismatch
+		}
+
+		version, err := nextSQLiteVersion(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		cas = strconv.FormatInt(version, 10)
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO records (key, version, value) VALUES (?, ?, ?)
+ON CONFLICT(key) DO UPDATE SET version = excluded.version, value = excluded.value`,
+			key, version, newValue)
+		return err
+	})
+	return cas, err
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
+
+func withSQLiteTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("store/sqlite: starting transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, ErrCASMismatch) {
+			return err
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func nextSQLiteVersion(ctx context.Context, tx *sql.Tx, key string) (int64, error) {
+	var version int64
+	row := tx.QueryRowContext(ctx, `SELECT version FROM records WHERE key = ?`, key)
+	switch err := row.Scan(&version); {
+	case errors.Is(err, sql.ErrNoRows):
+		return 1, nil
+	case err != nil:
+		return 0, fmt.Errorf("store/sqlite: reading current version: %w", err)
+	}
+	return version + 1, nil
+}
+
+func escapeSQLiteLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `%`, `\%`)
+	s = strings.ReplaceAll(s, `_`, `\_`)
+	return s
+}
@@ -0,0 +1,18 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+//go:build !nosqlite
+
+package store
+
+import "testing"
+
+func TestSQLiteStore_Conformance(t *testing.T) {
+	s, err := newSQLiteStore(":memory:")
+	if err != nil {
+		t.Fatalf("newSQLiteStore: %v", err)
+	}
+	defer s.Close()
+	conformance(t, s)
+}
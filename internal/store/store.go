@@ -0,0 +1,70 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+// Package store defines the persistent state backend SPIKE Nexus and
+// SPIKE Keeper use for the root key, admin credentials, and the admin
+// token, and provides several drivers behind that interface. Each driver
+// beyond the default in-memory one is guarded by a build tag (nobbolt,
+// nosqlite, nobadger, nopostgres) so an operator can compile a slim
+// binary containing exactly one of them next to the always-present
+// in-memory driver: for example,
+//
+//	go build -tags=nobbolt,nobadger,nosqlite ./...
+//
+// yields a build whose only persistent driver is Postgres.
+package store
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned by Get when the requested key does not exist.
+// AtomicUpdate reports a missing key as ErrCASMismatch instead.
+var ErrNotFound = errors.New("store: key not found")
+
+// ErrCASMismatch is returned by AtomicUpdate when the caller's expected
+// CAS token no longer matches the stored value, meaning another writer
+// updated it first.
+var ErrCASMismatch = errors.New("store: compare-and-swap token mismatch")
+
+// Record is a single stored value together with the opaque CAS token
+// that identifies the version of the value it was read at.
+type Record struct {
+	Value []byte
+	CAS   string
+}
+
+// Store is the persistence interface that every driver implements.
+// Drivers are free to choose their own CAS token representation (a
+// version counter, a content hash, a database row version, ...) as long
+// as AtomicUpdate only succeeds when the token passed in still matches
+// the token the value currently carries.
+type Store interface {
+	// Get returns the value stored at key, or ErrNotFound if it does
+	// not exist.
+	Get(ctx context.Context, key string) (Record, error)
+
+	// Put unconditionally writes value at key, creating or overwriting
+	// it, and returns the new CAS token.
+	Put(ctx context.Context, key string, value []byte) (string, error)
+
+	// Delete removes key. It is not an error to delete a key that does
+	// not exist.
+	Delete(ctx context.Context, key string) error
+
+	// List returns every key with the given prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// AtomicUpdate writes newValue at key only if the value currently
+	// stored there carries the CAS token expectedCAS. Passing "" as
+	// expectedCAS means "key must not exist yet". On success it
+	// returns the new CAS token; on a stale expectedCAS it returns
+	// ErrCASMismatch.
+	AtomicUpdate(ctx context.Context, key, expectedCAS string, newValue []byte) (string, error)
+
+	// Close releases any resources (file handles, connection pools)
+	// held by the driver.
+	Close() error
+}
@@ -0,0 +1,177 @@
+//    \\ SPIKE: Secure your secrets with SPIFFE.
+//  \\\\\ Copyright 2024-present SPIKE contributors.
+// \\\\\\\ SPDX-License-Identifier: Apache-2.0
+
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// conformance runs the same battery of assertions against any Store
+// implementation, so every driver is held to the same contract without
+// duplicating the test bodies per driver. Driver-specific test files
+// construct their Store and hand it to conformance.
+func conformance(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	t.Run("GetMissing_ReturnsErrNotFound", func(t *testing.T) {
+		if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Get(missing) error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("PutThenGet_RoundTrips", func(t *testing.T) {
+		cas, err := s.Put(ctx, "a", []byte("v1"))
+		if err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if cas == "" {
+			t.Fatalf("Put returned empty CAS token")
+		}
+
+		rec, err := s.Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if string(rec.Value) != "v1" || rec.CAS != cas {
+			t.Fatalf("Get = %+v, want Value=v1 CAS=%s", rec, cas)
+		}
+	})
+
+	t.Run("Put_OverwritesAndBumpsCAS", func(t *testing.T) {
+		firstCAS, _ := s.Put(ctx, "b", []byte("v1"))
+		secondCAS, err := s.Put(ctx, "b", []byte("v2"))
+		if err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if secondCAS == firstCAS {
+			t.Fatalf("Put did not change CAS token on overwrite")
+		}
+
+		rec, err := s.Get(ctx, "b")
+		if err != nil || string(rec.Value) != "v2" {
+			t.Fatalf("Get after overwrite = %+v, %v, want v2", rec, err)
+		}
+	})
+
+	t.Run("Delete_RemovesKey", func(t *testing.T) {
+		_, _ = s.Put(ctx, "c", []byte("v1"))
+		if err := s.Delete(ctx, "c"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := s.Get(ctx, "c"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Get after Delete error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("Delete_MissingKeyIsNotAnError", func(t *testing.T) {
+		if err := s.Delete(ctx, "never-existed"); err != nil {
+			t.Fatalf("Delete(missing) = %v, want nil", err)
+		}
+	})
+
+	t.Run("List_ReturnsOnlyMatchingPrefix", func(t *testing.T) {
+		_, _ = s.Put(ctx, "list/one", []byte("1"))
+		_, _ = s.Put(ctx, "list/two", []byte("2"))
+		_, _ = s.Put(ctx, "other/one", []byte("3"))
+
+		keys, err := s.List(ctx, "list/")
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(keys) != 2 {
+			t.Fatalf("List(list/) = %v, want 2 keys", keys)
+		}
+	})
+
+	t.Run("AtomicUpdate_EmptyCASRequiresAbsence", func(t *testing.T) {
+		if _, err := s.AtomicUpdate(ctx, "d", "", []byte("v1")); err != nil {
+			t.Fatalf("AtomicUpdate(create) : %v", err)
+		}
+		if _, err := s.AtomicUpdate(ctx, "d", "", []byte("v2")); !errors.Is(err, ErrCASMismatch) {
+			t.Fatalf("AtomicUpdate(create again) error = %v, want ErrCASMismatch", err)
+		}
+	})
+
+	t.Run("AtomicUpdate_StaleCASIsRejected", func(t *testing.T) {
+		cas, _ := s.Put(ctx, "e", []byte("v1"))
+		if _, err := s.AtomicUpdate(ctx, "e", "not-"+cas, []byte("v2")); !errors.Is(err, ErrCASMismatch) {
+			t.Fatalf("AtomicUpdate(stale) error = %v, want ErrCASMismatch", err)
+		}
+
+		newCAS, err := s.AtomicUpdate(ctx, "e", cas, []byte("v2"))
+		if err != nil {
+			t.Fatalf("AtomicUpdate(current): %v", err)
+		}
+		rec, err := s.Get(ctx, "e")
+		if err != nil || string(rec.Value) != "v2" || rec.CAS != newCAS {
+			t.Fatalf("Get after AtomicUpdate = %+v, %v", rec, err)
+		}
+	})
+}
+
+func TestMemoryStore_Conformance(t *testing.T) {
+	conformance(t, NewMemoryStore())
+}
+
+func TestEncryptedStore_Conformance(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	enc, err := NewEncryptedStore(NewMemoryStore(), key)
+	if err != nil {
+		t.Fatalf("NewEncryptedStore: %v", err)
+	}
+	conformance(t, enc)
+}
+
+func TestEncryptedStore_ValuesAreEncryptedAtRest(t *testing.T) {
+	inner := NewMemoryStore()
+	key := make([]byte, 32)
+	enc, err := NewEncryptedStore(inner, key)
+	if err != nil {
+		t.Fatalf("NewEncryptedStore: %v", err)
+	}
+
+	if _, err := enc.Put(context.Background(), "k", []byte("plaintext")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	raw, err := inner.Get(context.Background(), "k")
+	if err != nil {
+		t.Fatalf("inner Get: %v", err)
+	}
+	if string(raw.Value) == "plaintext" {
+		t.Fatalf("value was stored in the underlying driver unencrypted")
+	}
+}
+
+func TestMigrate_CopiesAllRecordsUnderPrefix(t *testing.T) {
+	src, dst := NewMemoryStore(), NewMemoryStore()
+	ctx := context.Background()
+
+	_, _ = src.Put(ctx, "keep/a", []byte("1"))
+	_, _ = src.Put(ctx, "keep/b", []byte("2"))
+	_, _ = src.Put(ctx, "skip/c", []byte("3"))
+
+	n, err := Migrate(ctx, src, dst, "keep/")
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("Migrate copied %d records, want 2", n)
+	}
+
+	if _, err := dst.Get(ctx, "skip/c"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Migrate copied a key outside the requested prefix")
+	}
+	rec, err := dst.Get(ctx, "keep/a")
+	if err != nil || string(rec.Value) != "1" {
+		t.Fatalf("dst.Get(keep/a) = %+v, %v", rec, err)
+	}
+}